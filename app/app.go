@@ -3,12 +3,20 @@ package app
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"fmt"
 	"github.com/cd365/hey-template/utils"
 	"github.com/cd365/hey-template/values"
+	"go/token"
 	"io"
+	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 	"unsafe"
@@ -16,6 +24,7 @@ import (
 	"github.com/cd365/hey/v2"
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 )
 
 const (
@@ -28,29 +37,44 @@ const (
 	tableFilenameGo     = ".go"
 )
 
+var (
+	// goModModule module github.com/cd365/hey-template
+	goModModule = regexp.MustCompile(`(?m)^module\s+"?([^\s"]+)"?`)
+)
+
+const (
+	tableTypeBaseTable        = "BASE TABLE"
+	tableTypeView             = "VIEW"
+	tableTypeMaterializedView = "MATERIALIZED VIEW"
+)
+
 type Helper interface {
-	QueryAllTable() error
+	QueryAllTable(ctx context.Context) error
 	GetAllTable() []*SchemaTable
-	QueryTableDefineSql(table *SchemaTable) error
+	QueryTableDefineSql(ctx context.Context, table *SchemaTable) error
 }
 
 type App struct {
 	Version string
 
+	ctx context.Context // 取消时停止查询数据库表结构 如: 收到 SIGINT 信号
+
 	cfg *Config
 
 	way *hey.Way
 
 	helper Helper
+
+	schemas []*App // 每个数据库模式(未配置多个模式时只有自身)
 }
 
 func NewApp(
 	ctx context.Context,
 	cfg *Config,
 ) *App {
-	_ = ctx
 	return &App{
 		Version: values.Version,
+		ctx:     ctx,
 		cfg:     cfg,
 	}
 }
@@ -58,20 +82,26 @@ func NewApp(
 func (s *App) initial() error {
 	cfg := s.cfg
 	cfg.Driver = strings.TrimSpace(cfg.Driver)
-	way, err := hey.NewWay(cfg.Driver, cfg.DataSourceName)
-	if err != nil {
-		return err
+	cfg.DataSourceName = strings.TrimSpace(cfg.DataSourceName)
+	// without data source, parse the table structure from ddl files
+	offline := cfg.DataSourceName == "" && len(cfg.DdlFiles) > 0
+	if !offline {
+		way, err := hey.NewWay(cfg.Driver, cfg.DataSourceName)
+		if err != nil {
+			return err
+		}
+		s.way = way
+		db := way.DB()
+		db.SetMaxOpenConns(cfg.Concurrency)
+		db.SetMaxIdleConns(cfg.Concurrency)
+		db.SetConnMaxIdleTime(time.Minute * 3)
+		db.SetConnMaxLifetime(time.Minute * 3)
 	}
-	s.way = way
-	db := way.DB()
-	db.SetMaxOpenConns(8)
-	db.SetMaxIdleConns(2)
-	db.SetConnMaxIdleTime(time.Minute * 3)
-	db.SetConnMaxLifetime(time.Minute * 3)
+	var newHelper func(app *App) Helper
 	switch cfg.Driver {
 	case hey.DriverNameMysql:
 		cfg.DatabaseIdentify = "`"
-		s.helper = NewMysql(s)
+		newHelper = NewMysql
 		if cfg.TableSchemaName == "" {
 			start := strings.Index(cfg.DataSourceName, "/")
 			if start > -1 {
@@ -86,16 +116,112 @@ func (s *App) initial() error {
 		}
 	case hey.DriverNamePostgres:
 		cfg.DatabaseIdentify = `"`
-		s.helper = NewPgsql(s)
+		newHelper = NewPgsql
 		if cfg.TableSchemaName == "" {
 			cfg.TableSchemaName = "public"
 		}
+	case hey.DriverNameSqlite3:
+		cfg.DatabaseIdentify = "`"
+		newHelper = NewSqlite
+		if cfg.TableSchemaName == "" {
+			cfg.TableSchemaName = "main"
+		}
 	default:
 		return fmt.Errorf("unsupported driver name: %s", cfg.Driver)
 	}
+	if offline {
+		newHelper = NewDdl
+	}
+	if len(cfg.TableSchemaList) == 0 {
+		s.helper = newHelper(s)
+		s.schemas = []*App{s}
+		return nil
+	}
+	// several schemas, each schema has its own config and helper
+	count := make(map[string]int)
+	for _, v := range cfg.TableSchemaList {
+		count[cfg.schemaPackage(v)]++
+	}
+	for _, v := range cfg.TableSchemaList {
+		tmp := *cfg
+		tmp.TableSchemaList = nil
+		tmp.TableSchemaName = v.Name
+		tmp.tableSchemaPrefix = v.Prefix
+		tmp.tableSchemaDefault = cfg.TableSchemaList[0].Name
+		if pkg := cfg.schemaPackage(v); pkg != cfg.Package {
+			tmp.Package = pkg
+			tmp.TemplateOutputDirectory = pathJoin(cfg.TemplateOutputDirectory, cfg.Package)
+		}
+		if count[tmp.Package] > 1 {
+			// the tables of several schemas are in the same package, the table name must be unique
+			tmp.UsingTableSchemaName = true
+		}
+		schema := &App{
+			Version: s.Version,
+			ctx:     s.ctx,
+			cfg:     &tmp,
+			way:     s.way,
+		}
+		schema.helper = newHelper(schema)
+		s.schemas = append(s.schemas, schema)
+	}
 	return nil
 }
 
+// parallel Run the tasks by a worker pool of config concurrency, the first failure cancels the other tasks.
+func (s *App) parallel(ctx context.Context, tasks ...func(ctx context.Context) error) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	once := &sync.Once{}
+	wg := &sync.WaitGroup{}
+	worker := make(chan struct{}, s.cfg.Concurrency)
+	for _, task := range tasks {
+		select {
+		case worker <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(task func(ctx context.Context) error) {
+			defer func() {
+				<-worker
+				wg.Done()
+			}()
+			if qer := task(ctx); qer != nil {
+				once.Do(func() {
+					err = qer
+					cancel()
+				})
+			}
+		}(task)
+	}
+	wg.Wait()
+	once.Do(func() { err = ctx.Err() })
+	return
+}
+
+// query Execute the query with the timeout of config.
+func (s *App) query(ctx context.Context, query func(rows *sql.Rows) error, prepare string, args ...interface{}) error {
+	if s.cfg.queryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.queryTimeout)
+		defer cancel()
+	}
+	return s.way.QueryContext(ctx, query, prepare, args...)
+}
+
+// takeAll Scan all rows of the query into result with the timeout of config.
+func (s *App) takeAll(ctx context.Context, result interface{}, prepare string, args ...interface{}) error {
+	if s.cfg.queryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.queryTimeout)
+		defer cancel()
+	}
+	return s.way.TakeAllContext(ctx, result, prepare, args...)
+}
+
 func (s *App) writeFile(reader io.Reader, filename string) error {
 	fil, err := utils.RemoveCreateFile(filename)
 	if err != nil {
@@ -126,17 +252,34 @@ func (s *App) BuildAll() error {
 	if err := s.initial(); err != nil {
 		return err
 	}
-	if s.cfg.Driver == hey.DriverNamePostgres {
-		if _, err := s.way.DB().Exec(pgsqlFuncCreate); err != nil {
+	ctx := s.ctx
+	if s.cfg.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.timeout)
+		defer cancel()
+	}
+	for _, schema := range s.schemas {
+		if err := schema.helper.QueryAllTable(ctx); err != nil {
 			return err
 		}
-		defer func() { _, _ = s.way.DB().Exec(pgsqlFuncDrop) }()
-	}
-	if err := s.helper.QueryAllTable(); err != nil {
-		return err
-	}
-	for _, table := range s.getAllTable(true) {
-		if err := s.helper.QueryTableDefineSql(table); err != nil {
+		tables := schema.getAllTable(true)
+		tasks := make([]func(ctx context.Context) error, 0, len(tables))
+		for _, table := range tables {
+			helper, table := schema.helper, table
+			tasks = append(tasks, func(ctx context.Context) error {
+				return helper.QueryTableDefineSql(ctx, table)
+			})
+		}
+		if s.way == nil {
+			// the statements of ddl files are output in order, the tables share the created types and sequences
+			for _, task := range tasks {
+				if err := task(ctx); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := s.parallel(ctx, tasks...); err != nil {
 			return err
 		}
 	}
@@ -156,7 +299,58 @@ type TableColumnPrimaryKey struct {
 	PrimaryKeyPascal      string // 主键名(帕斯卡命名)
 	PrimaryKeySmallPascal string // 主键名(驼峰命名)
 	PrimaryKeyUpper       string // 主键名(全大写) 如: ACCOUNT_USERNAME
-	PrimaryKeyType        string // 主键在go语言里面的类型(int | int64 | string), 其它类型无效
+	PrimaryKeyType        string // 主键在go语言里面的类型(int | int64 | uint32 | uint64 | string), 其它类型无效
+	PrimaryKeyName        string // 主键名(原始名称)
+
+	PrimaryKeyColumns    []*TableColumnPrimaryKey // 主键的所有字段(复合主键有多个字段)
+	PrimaryKeyComparable bool                     // 主键的所有字段是否都可以作为map的键
+}
+
+// TableUniqueIndex 唯一索引查询方法
+type TableUniqueIndex struct {
+	*Config
+	OriginNamePascal string                    // 表名(帕斯卡命名)
+	Indexes          []*TableUniqueIndexMethod // 表的所有唯一索引
+}
+
+// TableUniqueIndexMethod 单个唯一索引
+type TableUniqueIndexMethod struct {
+	IndexName string                    // 索引名称
+	By        string                    // 方法名后缀 如: TenantIdAndCode
+	Params    string                    // 方法参数 如: tenantId int, code string
+	Args      string                    // 方法实参 如: tenantId, code
+	Columns   []*TableUniqueIndexColumn // 索引的所有字段
+}
+
+// TableUniqueIndexColumn 唯一索引字段
+type TableUniqueIndexColumn struct {
+	Upper string // 字段名(全大写) 如: TENANT_ID
+	Param string // 参数名 如: tenantId
+}
+
+// TableRelation 外键关联查询方法
+type TableRelation struct {
+	*Config
+	OriginNamePascal string                 // 表名(帕斯卡命名)
+	Relations        []*TableRelationMethod // 表的所有关联查询方法
+}
+
+// TableRelationMethod 单个外键关联查询方法, row 是当前表的数据, v 是关联表的数据
+type TableRelationMethod struct {
+	Name           string // 方法名 如: LoadAccount
+	ConstraintName string // 外键约束名称
+	HasMany        bool   // 当前表被关联表引用(一对多)
+	Target         string // 关联表名(帕斯卡命名)
+	TargetTable    string // 关联表名(原始名称)
+	KeyDefine      string // 复合键类型定义
+	KeyType        string // 关联键类型 如: int | relationKey
+	KeysType       string // 关联键值列表类型 如: []interface{} | [][]interface{}
+	KeyValue       string // 关联键值 如: key | []interface{}{key.TenantId, key.Code}
+	Filter         string // 关联表查询条件 如: In(table.ID, keys...)
+	RowSkip        string // 当前表数据跳过条件 如: row.AccountId == nil
+	RowKey         string // 当前表数据的关联键 如: *row.AccountId
+	TargetSkip     string // 关联表数据跳过条件
+	TargetKey      string // 关联表数据的关联键 如: v.Id
 }
 
 type TmplTableModel struct {
@@ -164,11 +358,14 @@ type TmplTableModel struct {
 
 	table *SchemaTable
 
-	OriginName           string // 原始表名称
-	OriginNamePascal     string // 原始表名称(帕斯卡命名)
-	OriginNameWithPrefix string // 原始表名称
-	OriginNameCamel      string // 表名(帕斯卡命名)首字母小写表名
-	Comment              string // 表注释(如果表没有注释使用原始表名作为默认值)
+	OriginName           string   // 原始表名称
+	OriginNamePascal     string   // 原始表名称(帕斯卡命名)
+	OriginNameWithPrefix string   // 原始表名称
+	OriginNameCamel      string   // 表名(帕斯卡命名)首字母小写表名
+	Comment              string   // 表注释(如果表没有注释使用原始表名作为默认值)
+	Document             string   // 模型的文档注释 ==> 表类型, 主键, 索引, 外键, 分区
+	IsView               bool     // 是否为视图(视图和物化视图只生成查询方法)
+	Import               []string // 字段类型需要额外导入的包
 
 	// model
 	StructColumn                      []string // 表结构体字段定义 ==> Name string `json:"name" db:"name"` // 名称
@@ -189,12 +386,36 @@ type TmplTableModel struct {
 	ColumnCreatedAt string // 结构体字段方法 ColumnCreatedAt
 	ColumnUpdatedAt string // 结构体字段方法 ColumnUpdatedAt
 	ColumnDeletedAt string // 结构体字段方法 ColumnDeletedAt
-
-	PrimaryKey string // 主键自定义方法
+	ColumnGenerated string // 结构体字段方法 ColumnGenerated 生成列和 GENERATED ALWAYS AS IDENTITY 列, 不允许写入
+	ColumnTimestamp string // 结构体字段方法 ColumnTimestamp 日期时间类型的创建,更新,伪删除时间标记字段 如: s.CREATED_AT, s.UPDATED_AT
+	Indexes         string // 结构体字段方法 Indexes 表的所有索引 如: []*INDEX{ ... }
+
+	PrimaryKey  string // 主键自定义方法
+	UniqueIndex string // 唯一索引自定义方法
+	Check       string // 不能转换为验证规则的检查约束(注释)
+	Relation    string // 外键关联查询方法
 }
 
 func (s *TmplTableModel) prepare() error {
 
+	// import
+	{
+		// imported by the template
+		imports := map[string]struct{}{"database/sql": {}, "github.com/cd365/hey/v2": {}, "time": {}}
+		if !s.IsView {
+			imports["context"] = struct{}{}
+		}
+		for _, c := range s.table.Column {
+			for _, tmp := range c.goImports() {
+				if _, ok := imports[tmp]; !ok && tmp != "" {
+					imports[tmp] = struct{}{}
+					s.Import = append(s.Import, tmp)
+				}
+			}
+		}
+		sort.Strings(s.Import)
+	}
+
 	// struct define
 	for i, c := range s.table.Column {
 		tmp := fmt.Sprintf("\t%s %s `json:\"%s\" db:\"%s\"`",
@@ -213,6 +434,10 @@ func (s *TmplTableModel) prepare() error {
 		s.StructColumn = append(s.StructColumn, tmp)
 	}
 
+	// indexes and document
+	s.Indexes = s.indexes()
+	s.Document = s.document()
+
 	// schema
 	for i, c := range s.table.Column {
 		tmp := fmt.Sprintf("\t%s string", strings.ToUpper(*c.ColumnName))
@@ -297,8 +522,8 @@ func (s *TmplTableModel) prepare() error {
 			if v.ColumnName == nil || *v.ColumnName == "" {
 				continue
 			}
-			// make sure the type is integer
-			if !strings.Contains(v.databaseTypeToGoType(), "int") {
+			// make sure the type is integer, or time.Time of date or time column
+			if !strings.Contains(v.databaseTypeToGoType(), "int") && !v.isGoTime() {
 				continue
 			}
 			cm[*v.ColumnName] = v
@@ -346,9 +571,38 @@ func (s *TmplTableModel) prepare() error {
 			}
 			return fmt.Sprintf("[]string{ %s }", strings.Join(cols, ", "))
 		}
+		timestamp := make([]string, 0)
+		for _, v := range [][]string{created, updated, deleted} {
+			for _, c := range v {
+				if cm[c].isGoTime() {
+					timestamp = append(timestamp, fmt.Sprintf("s.%s", utils.Upper(c)))
+				}
+			}
+		}
+		s.ColumnTimestamp = strings.Join(timestamp, ", ")
 		s.ColumnCreatedAt = cs(created...)
 		s.ColumnUpdatedAt = cs(updated...)
 		s.ColumnDeletedAt = cs(deleted...)
+
+		// generated columns can not be inserted or updated
+		generated := make([]string, 0)
+		for _, v := range s.table.Column {
+			if v.ColumnName != nil && *v.ColumnName != "" && v.isGenerated() {
+				generated = append(generated, *v.ColumnName)
+			}
+		}
+		for _, field := range generated {
+			cannotBeUpdatedFieldsMap[field] = &struct{}{}
+		}
+		ignore = append(ignore, generated...)
+		s.ColumnGenerated = cs(generated...)
+	}
+
+	// views are read-only, no insert, update and primary key
+	if s.IsView {
+		s.ColumnAutoIncr = "nil"
+		s.ColumnGenerated = "nil"
+		return nil
 	}
 
 	ignoreMap := make(map[string]struct{})
@@ -356,20 +610,40 @@ func (s *TmplTableModel) prepare() error {
 		ignoreMap[v] = struct{}{}
 	}
 
+	primaryKeys := s.table.primaryKeys()
+	primaryKeyMap := make(map[string]*SchemaColumn, len(primaryKeys))
+	for _, c := range s.table.Column {
+		for _, v := range primaryKeys {
+			if v == *c.ColumnName {
+				primaryKeyMap[v] = c
+			}
+		}
+	}
+	if len(primaryKeyMap) != len(primaryKeys) {
+		primaryKeys = nil // the column of primary key does not exist
+	}
+	if untranslated := s.table.checkValidate(); len(untranslated) > 0 {
+		lines := make([]string, 0, len(untranslated)+1)
+		lines = append(lines, "// The check constraints are not translated into validate tags:")
+		for _, v := range untranslated {
+			lines = append(lines, fmt.Sprintf("// %s: CHECK (%s)", v.ConstraintName, strings.ReplaceAll(v.CheckClause, "\n", " ")))
+		}
+		s.Check = strings.Join(lines, "\n") + "\n"
+	}
+	if len(primaryKeys) > 0 {
+		s.StructColumnAddPrimaryKey = fmt.Sprintf("func (s INSERT%s) PrimaryKey() interface{} {\n\treturn nil\n}", s.table.pascal())
+	}
+
 	// add
 	write := false
 	for _, c := range s.table.Column {
 		if s.table.TableFieldSerial == *c.ColumnName {
-			s.StructColumnAddPrimaryKey = fmt.Sprintf("func (s INSERT%s) PrimaryKey() interface{} {\n\treturn nil\n}", s.table.pascal())
 			continue
 		}
 		if _, ok := ignoreMap[*c.ColumnName]; ok {
 			continue // ignore columns like id, created_at, updated_at, deleted_at
 		}
-		opts := ""
-		if c.CharacterMaximumLength != nil && *c.CharacterOctetLength > 0 {
-			opts = fmt.Sprintf(",min=0,max=%d", *c.CharacterMaximumLength)
-		}
+		opts := c.validate()
 		tmp := fmt.Sprintf("\t%s %s `json:\"%s\" db:\"%s\" validate:\"omitempty%s\"`",
 			c.pascal(),
 			c.databaseTypeToGoType(),
@@ -392,15 +666,15 @@ func (s *TmplTableModel) prepare() error {
 
 	// mod
 	write = false
-	delete(ignoreMap, s.table.TableFieldSerial)
+	for _, v := range primaryKeys {
+		delete(ignoreMap, v)
+	}
+	primaryKeyFields := make([]string, 0, len(primaryKeys))
 	for _, c := range s.table.Column {
 		if _, ok := ignoreMap[*c.ColumnName]; ok {
 			continue // ignore columns like created_at, updated_at, deleted_at
 		}
-		opts := ""
-		if c.CharacterMaximumLength != nil && *c.CharacterOctetLength > 0 {
-			opts = fmt.Sprintf(",min=0,max=%d", *c.CharacterMaximumLength)
-		}
+		opts := c.validate()
 		tmp := fmt.Sprintf("\t%s *%s `json:\"%s\" db:\"%s\" validate:\"omitempty%s\"`",
 			c.pascal(),
 			c.databaseTypeToGoType(),
@@ -408,27 +682,28 @@ func (s *TmplTableModel) prepare() error {
 			*c.ColumnName,
 			opts,
 		)
-		if *c.ColumnName == s.table.TableFieldSerial {
+		if _, ok := primaryKeyMap[*c.ColumnName]; ok {
 			// TableSerial
 			// TableSerial.SERIAL
 			comment := c.comment()
 			if comment != "" {
 				comment = fmt.Sprintf(" // %s", comment)
 			}
-			tablePascal := s.table.pascal()
-			columnPascal := c.pascal()
-			s.StructColumnPrimaryKey = fmt.Sprintf("type PRIMARY0KEY%s struct {\n\t%s *%s `json:\"%s\" db:\"-\" validate:\"omitempty,min=1\"`%s\n}\n\nfunc (s PRIMARY0KEY%s) PrimaryKey() interface{} {\n\t if s.%s != nil {\n\treturn *s.%s\n\t}\n\treturn nil\n}",
-				tablePascal,
-				columnPascal,
+			validate := "omitempty,min=1"
+			if len(primaryKeys) > 1 {
+				validate = "omitempty"
+			}
+			primaryKeyFields = append(primaryKeyFields, fmt.Sprintf("\t%s *%s `json:\"%s\" db:\"-\" validate:\"%s\"`%s",
+				c.pascal(),
 				c.databaseTypeToGoType(),
 				utils.Underline(*c.ColumnName),
+				validate,
 				comment,
-				tablePascal,
-				columnPascal,
-				columnPascal,
-			)
-			// append Primary-Key define
-			s.StructColumnMod = append(s.StructColumnMod, fmt.Sprintf("\tPRIMARY0KEY%s\n", tablePascal))
+			))
+			if len(primaryKeyFields) == 1 {
+				// append Primary-Key define
+				s.StructColumnMod = append(s.StructColumnMod, fmt.Sprintf("\tPRIMARY0KEY%s\n", s.table.pascal()))
+			}
 			continue
 		}
 
@@ -445,25 +720,66 @@ func (s *TmplTableModel) prepare() error {
 	}
 
 	// primary key
-	if s.table.TableFieldSerial != "" {
+	tablePascal := s.table.pascal()
+	switch len(primaryKeys) {
+	case 0:
+	case 1:
+		columnPascal := primaryKeyMap[primaryKeys[0]].pascal()
+		s.StructColumnPrimaryKey = fmt.Sprintf("type PRIMARY0KEY%s struct {\n%s\n}\n\nfunc (s PRIMARY0KEY%s) PrimaryKey() interface{} {\n\t if s.%s != nil {\n\treturn *s.%s\n\t}\n\treturn nil\n}",
+			tablePascal,
+			primaryKeyFields[0],
+			tablePascal,
+			columnPascal,
+			columnPascal,
+		)
+	default:
+		isNil := make([]string, 0, len(primaryKeys))
+		values := make([]string, 0, len(primaryKeys))
+		for _, v := range primaryKeys {
+			columnPascal := primaryKeyMap[v].pascal()
+			isNil = append(isNil, fmt.Sprintf("s.%s == nil", columnPascal))
+			values = append(values, fmt.Sprintf("%s: *s.%s", columnPascal, columnPascal))
+		}
+		s.StructColumnPrimaryKey = fmt.Sprintf("type PRIMARY0KEY%s struct {\n%s\n}\n\nfunc (s PRIMARY0KEY%s) PrimaryKey() interface{} {\n\tif %s {\n\t\treturn nil\n\t}\n\treturn KEY%s{%s}\n}",
+			tablePascal,
+			strings.Join(primaryKeyFields, "\n"),
+			tablePascal,
+			strings.Join(isNil, " || "),
+			tablePascal,
+			strings.Join(values, ", "),
+		)
+	}
+	if len(primaryKeys) > 0 {
+		content := tmplModelSchemaContentPrimaryKey
+		if len(primaryKeys) > 1 {
+			content = tmplModelSchemaContentPrimaryKeyComposite
+		}
 		tmpl := NewTemplate(
-			fmt.Sprintf("tmpl_model_schema_content_primary_key_%s_%s", *s.table.TableName, s.table.TableFieldSerial),
-			tmplModelSchemaContentPrimaryKey,
+			fmt.Sprintf("tmpl_model_schema_content_primary_key_%s_%s", *s.table.TableName, strings.Join(primaryKeys, "_")),
+			content,
 		)
 		buffer := bytes.NewBuffer(nil)
-		data := &TableColumnPrimaryKey{
-			OriginNamePascal:      s.table.pascal(),
-			PrimaryKeyPascal:      utils.Pascal(s.table.TableFieldSerial),
-			PrimaryKeySmallPascal: utils.PascalFirstLower(s.table.TableFieldSerial),
-			PrimaryKeyUpper:       strings.ToUpper(s.table.TableFieldSerial),
-		}
-		data.Config = s.table.app.cfg
-		for _, c := range s.table.Column {
-			if s.table.TableFieldSerial == *c.ColumnName {
-				data.PrimaryKeyType = strings.ToLower(c.databaseTypeToGoType())
-				break
+		data := &TableColumnPrimaryKey{}
+		for _, v := range primaryKeys {
+			c := primaryKeyMap[v]
+			column := &TableColumnPrimaryKey{
+				OriginNamePascal:      tablePascal,
+				PrimaryKeyName:        *c.ColumnName,
+				PrimaryKeyPascal:      c.pascal(),
+				PrimaryKeySmallPascal: c.pascalFirstLower(),
+				PrimaryKeyUpper:       c.upper(),
+				PrimaryKeyType:        c.databaseTypeToGoType(),
 			}
+			if len(data.PrimaryKeyColumns) == 0 {
+				*data = *column
+				data.PrimaryKeyComparable = true
+			}
+			if column.PrimaryKeyType == "[]byte" {
+				data.PrimaryKeyComparable = false
+			}
+			data.PrimaryKeyColumns = append(data.PrimaryKeyColumns, column)
 		}
+		data.Config = s.table.app.cfg
 		if err := tmpl.Execute(buffer, data); err != nil {
 			return err
 		} else {
@@ -471,9 +787,324 @@ func (s *TmplTableModel) prepare() error {
 		}
 	}
 
+	// unique index
+	if indexes := s.uniqueIndexes(primaryKeys); len(indexes) > 0 {
+		tmpl := NewTemplate(
+			fmt.Sprintf("tmpl_model_schema_content_unique_index_%s", *s.table.TableName),
+			tmplModelSchemaContentUniqueIndex,
+		)
+		buffer := bytes.NewBuffer(nil)
+		data := &TableUniqueIndex{
+			Config:           s.table.app.cfg,
+			OriginNamePascal: tablePascal,
+			Indexes:          indexes,
+		}
+		if err := tmpl.Execute(buffer, data); err != nil {
+			return err
+		}
+		s.UniqueIndex = buffer.String()
+	}
+
+	// relation
+	if relations := s.relations(); len(relations) > 0 {
+		tmpl := NewTemplate(
+			fmt.Sprintf("tmpl_model_schema_content_relation_%s", *s.table.TableName),
+			tmplModelSchemaContentRelation,
+		)
+		buffer := bytes.NewBuffer(nil)
+		data := &TableRelation{
+			Config:           s.table.app.cfg,
+			OriginNamePascal: tablePascal,
+			Relations:        relations,
+		}
+		if err := tmpl.Execute(buffer, data); err != nil {
+			return err
+		}
+		s.Relation = buffer.String()
+	}
+
 	return nil
 }
 
+// relations The batch loaders of foreign keys, belongs-to for the foreign keys of the table, has-many for the foreign keys which refer to the table.
+func (s *TmplTableModel) relations() []*TableRelationMethod {
+	type relation struct {
+		method  *TableRelationMethod
+		columns []string // the columns of foreign key, used to distinguish the methods with the same name
+	}
+	relations := make([]*relation, 0, len(s.table.TableForeignKey)+len(s.table.referencedBy))
+	names := make(map[string]int)
+	add := func(foreignKey *SchemaForeignKey, hasMany bool) {
+		rowColumns, target, targetColumns := foreignKey.Columns, foreignKey.referenced, foreignKey.ReferencedColumns
+		if hasMany {
+			rowColumns, target, targetColumns = foreignKey.ReferencedColumns, foreignKey.table, foreignKey.Columns
+		}
+		method := relationKey(s.table, rowColumns, target, targetColumns)
+		if method == nil {
+			return
+		}
+		method.ConstraintName = foreignKey.ConstraintName
+		method.HasMany = hasMany
+		method.Target = target.pascal()
+		method.TargetTable = *target.TableName
+		switch name := strings.TrimSuffix(foreignKey.Columns[0], "_id"); {
+		case hasMany:
+			method.Name = "Load" + target.pascal()
+		case len(foreignKey.Columns) == 1 && name != "":
+			method.Name = "Load" + utils.Pascal(name)
+		default:
+			method.Name = "Load" + target.pascal()
+		}
+		names[method.Name]++
+		relations = append(relations, &relation{method: method, columns: foreignKey.Columns})
+	}
+	for _, v := range s.table.TableForeignKey {
+		if v.referenced != nil {
+			add(v, false)
+		}
+	}
+	for _, v := range s.table.referencedBy {
+		add(v, true)
+	}
+	result := make([]*TableRelationMethod, 0, len(relations))
+	exists := make(map[string]struct{}, len(relations))
+	for _, v := range relations {
+		if names[v.method.Name] > 1 {
+			by := make([]string, 0, len(v.columns))
+			for _, c := range v.columns {
+				by = append(by, utils.Pascal(c))
+			}
+			v.method.Name = fmt.Sprintf("%sBy%s", v.method.Name, strings.Join(by, "And"))
+		}
+		if _, ok := exists[v.method.Name]; ok {
+			continue
+		}
+		exists[v.method.Name] = struct{}{}
+		result = append(result, v.method)
+	}
+	return result
+}
+
+// relationKey Build the key of relation, return nil if the types of columns are not comparable or can not be converted.
+func relationKey(table *SchemaTable, columns []string, target *SchemaTable, targetColumns []string) *TableRelationMethod {
+	if len(columns) == 0 || len(columns) != len(targetColumns) {
+		return nil
+	}
+	numeric := map[string]struct{}{
+		"int": {}, "int8": {}, "int16": {}, "int32": {}, "int64": {},
+		"uint": {}, "uint8": {}, "uint16": {}, "uint32": {}, "uint64": {},
+		"float32": {}, "float64": {},
+	}
+	method := &TableRelationMethod{}
+	fields, rowKeys, targetKeys, values, uppers := make([]string, 0), make([]string, 0), make([]string, 0), make([]string, 0), make([]string, 0)
+	rowSkip, targetSkip := make([]string, 0), make([]string, 0)
+	for i := range columns {
+		row, tmp := table.column(columns[i]), target.column(targetColumns[i])
+		if row == nil || tmp == nil {
+			return nil
+		}
+		rowType, targetType := row.databaseTypeToGoType(), tmp.databaseTypeToGoType()
+		rowKey, targetKey := "row."+row.pascal(), "v."+tmp.pascal()
+		if strings.HasPrefix(rowType, "*") {
+			rowType = rowType[1:]
+			rowSkip = append(rowSkip, rowKey+" == nil")
+			rowKey = "*" + rowKey
+		}
+		if strings.HasPrefix(targetType, "*") {
+			targetType = targetType[1:]
+			targetSkip = append(targetSkip, targetKey+" == nil")
+			targetKey = "*" + targetKey
+		}
+		if rowType == "[]byte" || targetType == "[]byte" {
+			return nil
+		}
+		if rowType != targetType {
+			_, ok1 := numeric[rowType]
+			_, ok2 := numeric[targetType]
+			if !ok1 || !ok2 {
+				return nil
+			}
+			targetKey = fmt.Sprintf("%s(%s)", rowType, targetKey)
+		}
+		fields = append(fields, fmt.Sprintf("%s %s", row.pascal(), rowType))
+		rowKeys = append(rowKeys, fmt.Sprintf("%s: %s", row.pascal(), rowKey))
+		targetKeys = append(targetKeys, fmt.Sprintf("%s: %s", row.pascal(), targetKey))
+		values = append(values, "key."+row.pascal())
+		uppers = append(uppers, "table."+tmp.upper())
+		if i == 0 {
+			method.KeyType, method.RowKey, method.TargetKey = rowType, rowKey, targetKey
+		}
+	}
+	method.RowSkip = strings.Join(rowSkip, " || ")
+	method.TargetSkip = strings.Join(targetSkip, " || ")
+	if len(columns) == 1 {
+		method.KeysType = "[]interface{}"
+		method.KeyValue = "key"
+		method.Filter = fmt.Sprintf("In(%s, keys...)", uppers[0])
+		return method
+	}
+	method.KeyDefine = fmt.Sprintf("type relationKey struct {\n\t\t%s\n\t}", strings.Join(fields, "\n\t\t"))
+	method.KeyType = "relationKey"
+	method.KeysType = "[][]interface{}"
+	method.KeyValue = fmt.Sprintf("[]interface{}{%s}", strings.Join(values, ", "))
+	method.Filter = fmt.Sprintf("InCols([]string{%s}, keys...)", strings.Join(uppers, ", "))
+	method.RowKey = fmt.Sprintf("relationKey{%s}", strings.Join(rowKeys, ", "))
+	method.TargetKey = fmt.Sprintf("relationKey{%s}", strings.Join(targetKeys, ", "))
+	return method
+}
+
+// tableIndexes The indexes of the table, the primary key is the first, others are sorted by name.
+// The expression indexes without any plain column are omitted, like: CREATE INDEX ON account (lower(email))
+func (s *TmplTableModel) tableIndexes() []*SchemaIndex {
+	result := make([]*SchemaIndex, 0, len(s.table.TableIndex))
+	for _, v := range s.table.TableIndex {
+		if len(v.Columns) > 0 {
+			result = append(result, v)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Primary != result[j].Primary {
+			return result[i].Primary
+		}
+		return result[i].IndexName < result[j].IndexName
+	})
+	return result
+}
+
+// indexes The value of method Indexes, like: []*INDEX{ {Name: "account_pkey", Columns: []string{"id"}, Unique: true, Primary: true, Method: "btree"} }
+func (s *TmplTableModel) indexes() string {
+	indexes := s.tableIndexes()
+	if len(indexes) == 0 {
+		return "nil"
+	}
+	items := make([]string, 0, len(indexes))
+	for _, v := range indexes {
+		columns := make([]string, 0, len(v.Columns))
+		for _, c := range v.Columns {
+			columns = append(columns, strconv.Quote(c))
+		}
+		items = append(items, fmt.Sprintf("\t\t{Name: %s, Columns: []string{%s}, Unique: %t, Primary: %t, Method: %s},",
+			strconv.Quote(v.IndexName),
+			strings.Join(columns, ", "),
+			v.Unique,
+			v.Primary,
+			strconv.Quote(v.Method),
+		))
+	}
+	return fmt.Sprintf("[]*INDEX{\n%s\n\t}", strings.Join(items, "\n"))
+}
+
+// document The lines of the doc comment below the title of the model, like: // Indexes:
+func (s *TmplTableModel) document() string {
+	lines := make([]string, 0, 8)
+	tableType := tableTypeBaseTable
+	if s.table.TableType != nil && *s.table.TableType != "" {
+		tableType = *s.table.TableType
+	}
+	lines = append(lines, "", fmt.Sprintf("Table: %s (%s)", s.OriginNameWithPrefix, tableType))
+	if primaryKeys := s.table.primaryKeys(); len(primaryKeys) > 0 && !s.IsView {
+		lines = append(lines, fmt.Sprintf("Primary key: %s", strings.Join(primaryKeys, ", ")))
+	}
+	if indexes := s.tableIndexes(); len(indexes) > 0 {
+		lines = append(lines, "Indexes:")
+		for _, v := range indexes {
+			kind := ""
+			switch {
+			case v.Primary:
+				kind = "PRIMARY KEY "
+			case v.Unique:
+				kind = "UNIQUE "
+			}
+			method := ""
+			if v.Method != "" {
+				method = v.Method + " "
+			}
+			lines = append(lines, fmt.Sprintf("  - %s: %s%s(%s)", v.IndexName, kind, method, strings.Join(v.Columns, ", ")))
+		}
+	}
+	if len(s.table.TableForeignKey) > 0 {
+		lines = append(lines, "Foreign keys:")
+		for _, v := range s.table.TableForeignKey {
+			referenced := v.ReferencedTable
+			if v.ReferencedSchema != "" && v.ReferencedSchema != *s.table.TableSchema {
+				referenced = fmt.Sprintf("%s.%s", v.ReferencedSchema, v.ReferencedTable)
+			}
+			lines = append(lines, fmt.Sprintf("  - %s: (%s) REFERENCES %s (%s)", v.ConstraintName, strings.Join(v.Columns, ", "), referenced, strings.Join(v.ReferencedColumns, ", ")))
+		}
+	}
+	if s.table.PartitionKey != "" {
+		lines = append(lines, fmt.Sprintf("Partitioned by: %s", strings.ReplaceAll(s.table.PartitionKey, "\n", " ")))
+		if len(s.table.Partitions) > 0 {
+			names := make([]string, 0, len(s.table.Partitions))
+			for _, v := range s.table.Partitions {
+				names = append(names, v.PartitionName)
+			}
+			lines = append(lines, fmt.Sprintf("Partitions: %s", strings.Join(names, ", ")))
+		}
+	}
+	for i := range lines {
+		lines[i] = strings.TrimRight("// "+lines[i], " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// uniqueIndexes The lookup methods of unique indexes, the index which is the same as the primary key or the previous index is ignored.
+func (s *TmplTableModel) uniqueIndexes(primaryKeys []string) []*TableUniqueIndexMethod {
+	columnMap := make(map[string]*SchemaColumn, len(s.table.Column))
+	for _, c := range s.table.Column {
+		columnMap[*c.ColumnName] = c
+	}
+	key := func(columns []string) string {
+		tmp := append([]string(nil), columns...)
+		sort.Strings(tmp)
+		return strings.Join(tmp, ",")
+	}
+	exists := make(map[string]struct{})
+	if len(primaryKeys) > 0 {
+		exists[key(primaryKeys)] = struct{}{}
+	}
+	// the parameter name can not be a keyword or the name of other parameters
+	reserved := map[string]struct{}{"s": {}, "f": {}, "u": {}, "hey": {}, "modify": {}, "ways": {}}
+	result := make([]*TableUniqueIndexMethod, 0, len(s.table.TableUniqueIndex))
+	for _, index := range s.table.TableUniqueIndex {
+		if len(index.Columns) == 0 {
+			continue
+		}
+		if _, ok := exists[key(index.Columns)]; ok {
+			continue
+		}
+		method := &TableUniqueIndexMethod{IndexName: index.IndexName}
+		by, params, args := make([]string, 0, len(index.Columns)), make([]string, 0, len(index.Columns)), make([]string, 0, len(index.Columns))
+		for _, name := range index.Columns {
+			c, ok := columnMap[name]
+			if !ok {
+				method = nil
+				break
+			}
+			param := c.pascalFirstLower()
+			if _, ok = reserved[param]; ok || token.IsKeyword(param) {
+				param += "Value"
+			}
+			by = append(by, c.pascal())
+			params = append(params, fmt.Sprintf("%s %s", param, strings.TrimPrefix(c.databaseTypeToGoType(), "*")))
+			args = append(args, param)
+			method.Columns = append(method.Columns, &TableUniqueIndexColumn{Upper: c.upper(), Param: param})
+		}
+		if method == nil {
+			continue
+		}
+		method.By = strings.Join(by, "And")
+		if method.By == "Column" {
+			continue // conflict with SelectOneByColumn, UpdateByColumn ...
+		}
+		method.Params = strings.Join(params, ", ")
+		method.Args = strings.Join(args, ", ")
+		exists[key(index.Columns)] = struct{}{}
+		result = append(result, method)
+	}
+	return result
+}
+
 type TmplTableModelSchema struct {
 	*Config
 	// data
@@ -481,23 +1112,160 @@ type TmplTableModelSchema struct {
 	NewDatabaseAttributeAssign      string // data_schema.go tables assign
 	NewDatabaseAttributeAssignMap   string // data_schema.go tables storage
 	NewDatabaseAttributeAssignSlice string // data_schema.go tables slice
+
+	NewDatabaseAttributeAssignViewMap   string // data_schema.go views storage
+	NewDatabaseAttributeAssignViewSlice string // data_schema.go views slice
+
+	DatabaseSchemaImport string // data_schema.go import the packages of schemas
+	DatabaseSchemaDefine string // data_schema.go schemas define
+	DatabaseSchemaAssign string // data_schema.go schemas assign
 }
 
 func (s *App) Model() error {
-	tables := s.getAllTable(false)
+	// the package of config first, then the package of each schema
+	packages := [][]*App{nil}
+	index := map[string]int{s.cfg.Package: 0}
+	for _, schema := range s.schemas {
+		i, ok := index[schema.cfg.Package]
+		if !ok {
+			i = len(packages)
+			index[schema.cfg.Package] = i
+			packages = append(packages, nil)
+		}
+		packages[i] = append(packages[i], schema)
+	}
+	for i := len(packages) - 1; i >= 0; i-- {
+		schemas := packages[i]
+		if i > 0 {
+			if err := s.model(schemas[0].cfg, schemas, nil); err != nil {
+				return err
+			}
+			continue
+		}
+		subs := make([]*App, 0, len(packages)-1)
+		for _, v := range packages[1:] {
+			subs = append(subs, v[0])
+		}
+		if err := s.model(s.cfg, schemas, subs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	pkg := s.cfg.Package
+// foreignKeyResolve Resolve the referenced tables of foreign keys, only the tables in the same package are resolved.
+func foreignKeyResolve(tables []*SchemaTable) {
+	tableMap := make(map[string]*SchemaTable, len(tables))
+	for _, v := range tables {
+		v.referencedBy = nil
+		if v.TableSchema != nil && !v.isView() {
+			tableMap[fmt.Sprintf("%s.%s", *v.TableSchema, *v.TableName)] = v
+		}
+	}
+	for _, v := range tables {
+		for _, foreignKey := range v.TableForeignKey {
+			foreignKey.table = v
+			foreignKey.referenced = tableMap[fmt.Sprintf("%s.%s", foreignKey.ReferencedSchema, foreignKey.ReferencedTable)]
+			if foreignKey.referenced == nil {
+				continue
+			}
+			// refer to the primary key of the referenced table implicitly
+			for _, column := range foreignKey.ReferencedColumns {
+				if column == "" {
+					foreignKey.ReferencedColumns = nil
+					break
+				}
+			}
+			if len(foreignKey.ReferencedColumns) == 0 {
+				foreignKey.ReferencedColumns = foreignKey.referenced.primaryKeys()
+			}
+			if len(foreignKey.ReferencedColumns) != len(foreignKey.Columns) {
+				foreignKey.referenced = nil
+				continue
+			}
+			foreignKey.referenced.referencedBy = append(foreignKey.referenced.referencedBy, foreignKey)
+		}
+	}
+}
+
+// columnJsonResolve Bind the custom types of config to the json columns.
+func columnJsonResolve(tables []*SchemaTable) {
+	for _, v := range tables {
+		for _, c := range v.Column {
+			c.json = nil
+			if c.DataType == nil || c.ColumnName == nil {
+				continue
+			}
+			if dataType := strings.ToLower(*c.DataType); dataType != "json" && dataType != "jsonb" {
+				continue
+			}
+			if tmp := v.app.cfg.columnJsonType(*v.TableName, *c.ColumnName); tmp != nil {
+				c.json = &SchemaJson{
+					ColumnJsonType: tmp,
+					name:           "JSON" + v.app.cfg.tableSchemaPrefix + utils.Pascal(identifier(*v.TableName+"_"+*c.ColumnName)),
+				}
+			}
+		}
+	}
+}
+
+// model Write the code of a package, subs are the schemas output to their own packages.
+func (s *App) model(cfg *Config, schemas []*App, subs []*App) error {
+	tables := make([]*SchemaTable, 0)
+	for _, schema := range schemas {
+		tables = append(tables, schema.getAllTable(false)...)
+	}
+	foreignKeyResolve(tables)
+	columnJsonResolve(tables)
+
+	pkg := cfg.Package
 
 	tmpModelSchema := NewTemplate("tmpl_model_schema", tmplModelSchema)
 	tmpModelSchemaContent := NewTemplate("tmpl_model_schema_content", tmplModelSchemaContent)
-	modelSchemaFilename := pathJoin(s.cfg.TemplateOutputDirectory, pkg, "aaa_schema.go")
+	modelSchemaFilename := pathJoin(cfg.TemplateOutputDirectory, pkg, "aaa_schema.go")
 	modelSchemaBuffer := bytes.NewBuffer(nil)
-	modelTableCreateFilename := pathJoin(s.cfg.TemplateOutputDirectory, pkg, "aaa_table_create.sql")
+	modelTableCreateFilename := pathJoin(cfg.TemplateOutputDirectory, pkg, "aaa_table_create.sql")
 	modelTableCreateBuffer := bytes.NewBuffer(nil)
 
-	for index, table := range tables {
-		// for table ddl
-		{
+	for _, table := range tables {
+		modelSchemaContentBuffer := bytes.NewBuffer(nil)
+		tmp, err := table.newTmplTableModel()
+		if err != nil {
+			return err
+		}
+		if err = tmpModelSchemaContent.Execute(modelSchemaContentBuffer, tmp); err != nil {
+			return err
+		}
+		name := *table.TableName
+		if len(schemas) > 1 {
+			name = fmt.Sprintf("%s_%s", table.app.cfg.TableSchemaName, name)
+		}
+		modelSchemaContentFilename := pathJoin(cfg.TemplateOutputDirectory, pkg, fmt.Sprintf("%s%s%s%s", tableFilenamePrefix, name, tableFilenameSuffix, tableFilenameGo))
+		// zzz_xxx_aaa.go
+		if err = s.writeFile(modelSchemaContentBuffer, modelSchemaContentFilename); err != nil {
+			return err
+		}
+	}
+
+	// for table ddl, views are created after the tables they depend on
+	for i, schema := range schemas {
+		if len(schemas) > 1 {
+			// switch to the schema
+			head := fmt.Sprintf("/* schema: %s */\n", schema.cfg.TableSchemaName)
+			switch cfg.Driver {
+			case hey.DriverNameMysql:
+				head += fmt.Sprintf("USE `%s`;\n", schema.cfg.TableSchemaName)
+			case hey.DriverNamePostgres:
+				head += fmt.Sprintf("SET search_path TO \"%s\";\n", schema.cfg.TableSchemaName)
+			}
+			if i > 0 {
+				head = "\n\n\n\n" + head
+			}
+			if _, err := modelTableCreateBuffer.WriteString(head); err != nil {
+				return err
+			}
+		}
+		for index, table := range ddlOrder(schema.getAllTable(false)) {
 			ddl := table.DDL
 			for strings.HasSuffix(ddl, "\n") {
 				ddl = strings.TrimSuffix(ddl, "\n")
@@ -512,8 +1280,8 @@ func (s *App) Model() error {
 				return err
 			}
 			// add drop table sql
-			dropTableName := fmt.Sprintf("%s%s%s", s.cfg.DatabaseIdentify, *table.TableName, s.cfg.DatabaseIdentify)
-			if _, err := modelTableCreateBuffer.WriteString(fmt.Sprintf("DROP TABLE IF EXISTS %s;\n", dropTableName)); err != nil {
+			dropTableName := fmt.Sprintf("%s%s%s", cfg.DatabaseIdentify, *table.TableName, cfg.DatabaseIdentify)
+			if _, err := modelTableCreateBuffer.WriteString(fmt.Sprintf("DROP %s IF EXISTS %s;\n", table.dropType(), dropTableName)); err != nil {
 				return err
 			}
 			if _, err := modelTableCreateBuffer.WriteString(ddl); err != nil {
@@ -525,36 +1293,28 @@ func (s *App) Model() error {
 				}
 			}
 		}
-		// table
-		modelSchemaContentBuffer := bytes.NewBuffer(nil)
-		tmp, err := table.newTmplTableModel()
-		if err != nil {
-			return err
-		}
-		if err = tmpModelSchemaContent.Execute(modelSchemaContentBuffer, tmp); err != nil {
-			return err
-		}
-		modelSchemaContentFilename := pathJoin(s.cfg.TemplateOutputDirectory, pkg, fmt.Sprintf("%s%s%s%s", tableFilenamePrefix, *table.TableName, tableFilenameSuffix, tableFilenameGo))
-		// zzz_xxx_aaa.go
-		if err = s.writeFile(modelSchemaContentBuffer, modelSchemaContentFilename); err != nil {
-			return err
-		}
-
 	}
 
 	// aaa_schema.go
 	{
 		schema := &TmplTableModelSchema{}
-		schema.Config = s.cfg
+		schema.Config = cfg
 		length := len(tables)
 		defines := make([]string, 0, length)
 		assigns := make([]string, 0, length)
 		storage := make([]string, 0, length)
 		slice := make([]string, 0, length)
+		viewStorage := make([]string, 0)
+		viewSlice := make([]string, 0)
 		for _, table := range tables {
 			namePascal := table.pascal()
-			defines = append(defines, fmt.Sprintf("%s *%s%s", namePascal, s.cfg.Schema, namePascal))
-			assigns = append(assigns, fmt.Sprintf("%s: new%s%s(basic, way),", namePascal, s.cfg.Schema, namePascal))
+			defines = append(defines, fmt.Sprintf("%s *%s%s", namePascal, cfg.Schema, namePascal))
+			assigns = append(assigns, fmt.Sprintf("%s: new%s%s(basic, way),", namePascal, cfg.Schema, namePascal))
+			if table.isView() {
+				viewStorage = append(viewStorage, fmt.Sprintf("tmp.%s.Table(): tmp.%s,", namePascal, namePascal))
+				viewSlice = append(viewSlice, fmt.Sprintf("tmp.%s.Table(),", namePascal))
+				continue
+			}
 			storage = append(storage, fmt.Sprintf("tmp.%s.Table(): tmp.%s,", namePascal, namePascal))
 			slice = append(slice, fmt.Sprintf("tmp.%s.Table(),", namePascal))
 		}
@@ -562,6 +1322,26 @@ func (s *App) Model() error {
 		schema.NewDatabaseAttributeAssign = strings.Join(assigns, "\n\t\t")
 		schema.NewDatabaseAttributeAssignMap = strings.Join(storage, "\n\t\t")
 		schema.NewDatabaseAttributeAssignSlice = strings.Join(slice, "\n\t\t")
+		schema.NewDatabaseAttributeAssignViewMap = strings.Join(viewStorage, "\n\t\t")
+		schema.NewDatabaseAttributeAssignViewSlice = strings.Join(viewSlice, "\n\t\t")
+		if len(subs) > 0 {
+			importPath, err := s.importPath()
+			if err != nil {
+				return err
+			}
+			imports := make([]string, 0, len(subs))
+			defines = make([]string, 0, len(subs))
+			assigns = make([]string, 0, len(subs))
+			for _, sub := range subs {
+				name := utils.Pascal(sub.cfg.TableSchemaName)
+				imports = append(imports, fmt.Sprintf("\"%s/%s\"", importPath, sub.cfg.Package))
+				defines = append(defines, fmt.Sprintf("%s *%s.Database // schema %s", name, sub.cfg.Package, sub.cfg.TableSchemaName))
+				assigns = append(assigns, fmt.Sprintf("if db, err := %s.NewDatabase(ctx, way, nil); err != nil {\n\t\treturn nil, err\n\t} else {\n\t\ttmp.%s = db\n\t}", sub.cfg.Package, name))
+			}
+			schema.DatabaseSchemaImport = strings.Join(imports, "\n\t")
+			schema.DatabaseSchemaDefine = strings.Join(defines, "\n\t")
+			schema.DatabaseSchemaAssign = strings.Join(assigns, "\n\t")
+		}
 		if err := tmpModelSchema.Execute(modelSchemaBuffer, schema); err != nil {
 			return err
 		}
@@ -577,9 +1357,263 @@ func (s *App) Model() error {
 		}
 	}
 
+	// aaa_enum.go
+	if enums := tmplEnums(tables); len(enums) > 0 {
+		data := &TmplEnum{Config: cfg, Enums: enums}
+		for _, v := range enums {
+			if v.Set != "" {
+				data.HasSet = true
+			}
+		}
+		buffer := bytes.NewBuffer(nil)
+		if err := NewTemplate("tmpl_model_enum", tmplModelEnum).Execute(buffer, data); err != nil {
+			return err
+		}
+		if err := s.writeFile(buffer, pathJoin(cfg.TemplateOutputDirectory, pkg, "aaa_enum.go")); err != nil {
+			return err
+		}
+	}
+
+	// aaa_decimal.go, aaa_bit.go, aaa_network.go, aaa_interval.go
+	for _, v := range []struct {
+		name  string
+		tmpl  []byte
+		file  string
+		types []string
+	}{
+		{"tmpl_model_decimal", tmplModelDecimal, "aaa_decimal.go", []string{"DECIMAL"}},
+		{"tmpl_model_bit", tmplModelBit, "aaa_bit.go", []string{"BIT"}},
+		{"tmpl_model_network", tmplModelNetwork, "aaa_network.go", []string{"INET", "CIDR"}},
+		{"tmpl_model_interval", tmplModelInterval, "aaa_interval.go", []string{"INTERVAL"}},
+	} {
+		if !tablesGoTypeExists(tables, v.types...) {
+			continue
+		}
+		buffer := bytes.NewBuffer(nil)
+		if err := NewTemplate(v.name, v.tmpl).Execute(buffer, cfg); err != nil {
+			return err
+		}
+		if err := s.writeFile(buffer, pathJoin(cfg.TemplateOutputDirectory, pkg, v.file)); err != nil {
+			return err
+		}
+	}
+
+	// aaa_json.go
+	if types := tmplJsonTypes(tables); len(types) > 0 {
+		data := &TmplJson{Config: cfg, Types: types}
+		exists := make(map[string]struct{})
+		for _, v := range types {
+			if _, ok := exists[v.Import]; !ok && v.Import != "" {
+				exists[v.Import] = struct{}{}
+				data.Import = append(data.Import, v.Import)
+			}
+		}
+		sort.Strings(data.Import)
+		buffer := bytes.NewBuffer(nil)
+		if err := NewTemplate("tmpl_model_json", tmplModelJson).Execute(buffer, data); err != nil {
+			return err
+		}
+		if err := s.writeFile(buffer, pathJoin(cfg.TemplateOutputDirectory, pkg, "aaa_json.go")); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// tablesGoTypeExists Whether any column of tables uses one of the generated go types, like: DECIMAL, *DECIMAL.
+func tablesGoTypeExists(tables []*SchemaTable, types ...string) bool {
+	for _, table := range tables {
+		for _, c := range table.Column {
+			tmp := strings.TrimPrefix(c.databaseTypeToGoType(), "*")
+			for _, v := range types {
+				if tmp == v {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// TmplJson JSON字段绑定的自定义类型
+type TmplJson struct {
+	*Config
+	Import []string        // 自定义类型需要导入的包
+	Types  []*TmplJsonType // 包中所有JSON字段的包装类型
+}
+
+// TmplJsonType 单个JSON字段的包装类型
+type TmplJsonType struct {
+	Name   string // 包装类型名称 如: JSONAccountProfile
+	Column string // 字段 如: account.profile
+	Type   string // 自定义类型 如: types.Profile
+	Import string // 自定义类型所在包的导入路径
+}
+
+// tmplJsonTypes The wrapper types of json columns which are bound to custom types.
+func tmplJsonTypes(tables []*SchemaTable) []*TmplJsonType {
+	result := make([]*TmplJsonType, 0)
+	for _, table := range tables {
+		for _, c := range table.Column {
+			if c.json == nil {
+				continue
+			}
+			result = append(result, &TmplJsonType{
+				Name:   c.json.name,
+				Column: c.json.Column,
+				Type:   c.json.Type,
+				Import: c.json.Import,
+			})
+		}
+	}
+	return result
+}
+
+// TmplEnum 枚举类型
+type TmplEnum struct {
+	*Config
+	Enums  []*TmplEnumType // 包中所有表使用的枚举类型
+	HasSet bool            // 是否有集合类型
+}
+
+// TmplEnumType 单个枚举类型
+type TmplEnumType struct {
+	Name      string           // go类型名称 如: ENUMMood
+	Set       string           // 集合类型名称(mysql SET) 如: SETUserTags
+	EnumName  string           // 枚举类型名称 如: mood
+	ValueList string           // 所有常量 如: ENUMMoodHappy, ENUMMoodSad
+	Values    []*TmplEnumValue // 所有枚举值
+}
+
+// TmplEnumValue 枚举值
+type TmplEnumValue struct {
+	Name  string // 常量名称 如: ENUMMoodHappy
+	Value string // 常量值 如: "happy"
+}
+
+// tmplEnums The enum types used by the columns of tables, in the order of first use.
+func tmplEnums(tables []*SchemaTable) []*TmplEnumType {
+	result := make([]*TmplEnumType, 0)
+	exists := make(map[string]struct{})
+	for _, table := range tables {
+		for _, c := range table.Column {
+			if c.enum == nil {
+				continue
+			}
+			name := c.enum.goName()
+			if _, ok := exists[name]; ok {
+				continue
+			}
+			exists[name] = struct{}{}
+			tmp := &TmplEnumType{Name: name, EnumName: c.enum.EnumName}
+			if c.enum.Set {
+				tmp.Set = c.enum.setName()
+			}
+			names := make([]string, 0, len(c.enum.Values))
+			used := make(map[string]struct{}, len(c.enum.Values))
+			for i, v := range c.enum.Values {
+				label := identifier(v)
+				if strings.ToUpper(label) == label {
+					label = strings.ToLower(label) // IN_PROGRESS => InProgress
+				}
+				constant := name + utils.Pascal(label)
+				if _, ok := used[constant]; ok || constant == name {
+					constant = fmt.Sprintf("%s%d", name, i)
+				}
+				used[constant] = struct{}{}
+				names = append(names, constant)
+				tmp.Values = append(tmp.Values, &TmplEnumValue{Name: constant, Value: strconv.Quote(v)})
+			}
+			tmp.ValueList = strings.Join(names, ", ")
+			result = append(result, tmp)
+		}
+	}
+	return result
+}
+
+// importPath The import path of the package of config, it is inferred from go.mod if it is not configured.
+func (s *App) importPath() (string, error) {
+	if s.cfg.ImportPath != "" {
+		return strings.TrimSuffix(s.cfg.ImportPath, "/"), nil
+	}
+	dir, err := filepath.Abs(pathJoin(s.cfg.TemplateOutputDirectory, s.cfg.Package))
+	if err != nil {
+		return "", err
+	}
+	for parent := dir; ; parent = filepath.Dir(parent) {
+		content, err := os.ReadFile(pathJoin(parent, "go.mod"))
+		if err == nil {
+			module := goModModule.FindSubmatch(content)
+			if module == nil {
+				break
+			}
+			rel, err := filepath.Rel(parent, dir)
+			if err != nil {
+				return "", err
+			}
+			return path.Join(string(module[1]), filepath.ToSlash(rel)), nil
+		}
+		if filepath.Dir(parent) == parent {
+			break
+		}
+	}
+	return "", fmt.Errorf("unable to infer the import path of package %s, please set import_path", s.cfg.Package)
+}
+
+// ddlOrder Tables first, then the views, a view is placed after the views it references.
+func ddlOrder(tables []*SchemaTable) []*SchemaTable {
+	result := make([]*SchemaTable, 0, len(tables))
+	views := make([]*SchemaTable, 0)
+	for _, table := range tables {
+		if table.isView() {
+			views = append(views, table)
+			continue
+		}
+		result = append(result, table)
+	}
+	reference := func(view *SchemaTable, name string) bool {
+		if *view.TableName == name {
+			return false
+		}
+		return regexp.MustCompile(fmt.Sprintf(`(^|[^A-Za-z0-9_$])%s($|[^A-Za-z0-9_$])`, regexp.QuoteMeta(name))).MatchString(view.DDL)
+	}
+	done := make(map[*SchemaTable]*struct{}, len(views))
+	for len(done) < len(views) {
+		added := false
+		for _, view := range views {
+			if _, ok := done[view]; ok {
+				continue
+			}
+			ready := true
+			for _, other := range views {
+				if _, ok := done[other]; ok || other == view {
+					continue
+				}
+				if reference(view, *other.TableName) {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				done[view] = &struct{}{}
+				result = append(result, view)
+				added = true
+			}
+		}
+		if !added {
+			// circular reference, keep the original order
+			for _, view := range views {
+				if _, ok := done[view]; !ok {
+					done[view] = &struct{}{}
+					result = append(result, view)
+				}
+			}
+		}
+	}
+	return result
+}
+
 func NewTemplate(name string, content []byte) *template.Template {
 	return template.Must(template.New(name).Delims(templateLeft, templateRight).Parse(*(*string)(unsafe.Pointer(&content))))
 }
@@ -590,21 +1624,102 @@ func pathJoin(items ...string) string {
 
 // SchemaTable 数据库表结构
 type SchemaTable struct {
-	app              *App            `db:"-"`
-	TableSchema      *string         `db:"table_schema"`  // 数据库名
-	TableName        *string         `db:"table_name"`    // 表名
-	TableComment     *string         `db:"table_comment"` // 表注释
-	TableFieldSerial string          `db:"-"`             // 表自动递增字段
-	Column           []*SchemaColumn `db:"-"`             // 表中的所有字段
-	DDL              string          `db:"-"`             // 表定义语句
+	app              *App                `db:"-"`
+	TableSchema      *string             `db:"table_schema"`  // 数据库名
+	TableName        *string             `db:"table_name"`    // 表名
+	TableComment     *string             `db:"table_comment"` // 表注释
+	TableType        *string             `db:"table_type"`    // 表类型 BASE TABLE | VIEW | MATERIALIZED VIEW
+	TableFieldSerial string              `db:"-"`             // 表自动递增字段
+	TablePrimaryKey  []string            `db:"-"`             // 表主键字段(按主键约束中的顺序)
+	TableUniqueIndex []*SchemaIndex      `db:"-"`             // 表唯一索引(包括唯一约束,不包括主键)
+	TableIndex       []*SchemaIndex      `db:"-"`             // 表的所有索引(包括主键)
+	TableForeignKey  []*SchemaForeignKey `db:"-"`             // 表外键
+	TableCheck       []*SchemaCheck      `db:"-"`             // 表检查约束
+	Column           []*SchemaColumn     `db:"-"`             // 表中的所有字段
+	DDL              string              `db:"-"`             // 表定义语句
+	PartitionKey     string              `db:"-"`             // 分区键 如: RANGE (created_at) (分区表)
+	Partitions       []*SchemaPartition  `db:"-"`             // 表分区(分区表, 分区不会作为单独的表)
+
+	referencedBy []*SchemaForeignKey `db:"-"` // 引用当前表的外键(同一个包中的表)
+}
+
+// SchemaForeignKey 表外键
+type SchemaForeignKey struct {
+	ConstraintName    string   // 约束名称
+	Columns           []string // 外键字段(按约束中的顺序)
+	ReferencedSchema  string   // 引用的数据库名
+	ReferencedTable   string   // 引用的表名
+	ReferencedColumns []string // 引用的字段(与外键字段一一对应)
+
+	table      *SchemaTable // 外键所在的表
+	referenced *SchemaTable // 引用的表(同一个包中的表, 不存在时为nil)
+}
+
+// SchemaCheck 表检查约束
+type SchemaCheck struct {
+	ConstraintName string // 约束名称
+	CheckClause    string // 检查条件 如: price > 0
+}
+
+// SchemaPartition 表分区
+type SchemaPartition struct {
+	PartitionName  string             // 分区名称
+	PartitionBound string             // 分区范围 如: FOR VALUES FROM ('2024-01-01') TO ('2024-02-01') | VALUES LESS THAN (2024)
+	PartitionKey   string             // 子分区的分区键(postgresql)
+	Partitions     []*SchemaPartition // 子分区(postgresql)
+}
+
+// SchemaIndex 表索引
+type SchemaIndex struct {
+	IndexName string   // 索引名称
+	Unique    bool     // 是否唯一
+	Columns   []string // 索引字段(按索引中的顺序)
+	Primary   bool     // 是否为主键
+	Method    string   // 索引方法 如: btree, hash, gin, BTREE, FULLTEXT
+}
+
+func (s *SchemaTable) isView() bool {
+	if s.TableType == nil {
+		return false
+	}
+	return *s.TableType == tableTypeView || *s.TableType == tableTypeMaterializedView
+}
+
+// dropType The object type of DROP statement.
+func (s *SchemaTable) dropType() string {
+	if s.TableType == nil || !s.isView() {
+		return "TABLE"
+	}
+	return *s.TableType
+}
+
+// primaryKeys The columns of primary key, the auto increment column is used if the table has no primary key constraint.
+func (s *SchemaTable) primaryKeys() []string {
+	if len(s.TablePrimaryKey) > 0 {
+		return s.TablePrimaryKey
+	}
+	if s.TableFieldSerial != "" {
+		return []string{s.TableFieldSerial}
+	}
+	return nil
+}
+
+// column Get the column by name, nil if the table has no such column.
+func (s *SchemaTable) column(name string) *SchemaColumn {
+	for _, c := range s.Column {
+		if c.ColumnName != nil && *c.ColumnName == name {
+			return c
+		}
+	}
+	return nil
 }
 
 func (s *SchemaTable) pascal() string {
-	return utils.Pascal(*s.TableName)
+	return s.app.cfg.tableSchemaPrefix + utils.Pascal(*s.TableName)
 }
 
 func (s *SchemaTable) pascalFirstLower() string {
-	return utils.PascalFirstLower(*s.TableName)
+	return utils.PascalFirstLower(s.pascal())
 }
 
 func (s *SchemaTable) comment() string {
@@ -622,6 +1737,7 @@ func (s *SchemaTable) newTmplTableModel() (*TmplTableModel, error) {
 		OriginNameWithPrefix: *s.TableName,
 		OriginNameCamel:      s.pascalFirstLower(),
 		Comment:              *s.TableName,
+		IsView:               s.isView(),
 	}
 	tmp.Config = s.app.cfg
 	if s.app.cfg.UsingTableSchemaName && s.app.cfg.TableSchemaName != "" {
@@ -656,6 +1772,120 @@ type SchemaColumn struct {
 	ColumnType             *string      `db:"column_type"`              // 列类型
 	ColumnKey              *string      `db:"column_key"`               // 列索引 '', 'PRI', 'UNI', 'MUL'
 	Extra                  *string      `db:"extra"`                    // 列额外属性 auto_increment
+	UdtSchema              *string      `db:"udt_schema"`               // 列类型所在的模式(postgresql)
+	UdtName                *string      `db:"udt_name"`                 // 列类型名称(postgresql)
+	IsGenerated            *string      `db:"is_generated"`             // 是否为生成列 ALWAYS, NEVER (postgresql)
+	IsIdentity             *string      `db:"is_identity"`              // 是否为标识列 YES, NO (postgresql)
+	IdentityGeneration     *string      `db:"identity_generation"`      // 标识列生成方式 ALWAYS, BY DEFAULT (postgresql)
+	GenerationExpression   *string      `db:"generation_expression"`    // 生成列的表达式(postgresql)
+
+	enum  *SchemaEnum `db:"-"` // 列的枚举类型
+	check string      `db:"-"` // 检查约束转换的验证规则 如: ,gt=0
+	json  *SchemaJson `db:"-"` // JSON列绑定的自定义类型
+}
+
+// SchemaJson JSON列绑定的自定义类型
+type SchemaJson struct {
+	*ColumnJsonType
+	name string // 包装类型名称 如: JSONAccountProfile
+}
+
+// SchemaEnum 枚举类型
+type SchemaEnum struct {
+	EnumSchema string   // 枚举类型所在的模式
+	EnumName   string   // 枚举类型名称
+	Values     []string // 枚举值(按定义中的顺序)
+	Set        bool     // 是否为集合类型(mysql SET), 列值是以逗号分隔的多个枚举值
+
+	prefix string // 类型名前缀, 用于区分不同模式的同名枚举类型
+}
+
+// goName The name of go type, such as: ENUMMood
+func (s *SchemaEnum) goName() string {
+	return "ENUM" + s.prefix + utils.Pascal(identifier(s.EnumName))
+}
+
+// setName The name of go slice type of mysql SET column, such as: SETUserTags
+func (s *SchemaEnum) setName() string {
+	return "SET" + s.prefix + utils.Pascal(identifier(s.EnumName))
+}
+
+// oneof The values of validate oneof tag, it is empty if some values can not be expressed in the tag.
+func (s *SchemaEnum) oneof() string {
+	if s.Set {
+		return ""
+	}
+	for _, v := range s.Values {
+		if v == "" || strings.ContainsAny(v, " ,|'\"`") {
+			return ""
+		}
+	}
+	return strings.Join(s.Values, " ")
+}
+
+// columnEnum The enum type of mysql ENUM or SET column, the values are parsed from the column type like enum('a','b').
+func columnEnum(column *SchemaColumn) *SchemaEnum {
+	if column.ColumnType == nil || column.TableName == nil || column.ColumnName == nil {
+		return nil
+	}
+	columnType := strings.TrimSpace(*column.ColumnType)
+	lower := strings.ToLower(columnType)
+	enum := &SchemaEnum{EnumName: fmt.Sprintf("%s_%s", *column.TableName, *column.ColumnName)}
+	if column.TableSchema != nil {
+		enum.EnumSchema = *column.TableSchema
+	}
+	if column.table != nil && column.table.app != nil {
+		enum.prefix = column.table.app.cfg.tableSchemaPrefix
+	}
+	switch {
+	case strings.HasPrefix(lower, "enum(") && strings.HasSuffix(lower, ")"):
+		columnType = columnType[5 : len(columnType)-1]
+	case strings.HasPrefix(lower, "set(") && strings.HasSuffix(lower, ")"):
+		columnType = columnType[4 : len(columnType)-1]
+		enum.Set = true
+	default:
+		return nil
+	}
+	// 'a','it''s','b\'c'
+	for i := 0; i < len(columnType); i++ {
+		quote := columnType[i]
+		if quote != '\'' && quote != '"' {
+			continue
+		}
+		value := make([]byte, 0, 16)
+		for i++; i < len(columnType); i++ {
+			if columnType[i] == '\\' && i+1 < len(columnType) {
+				i++
+				value = append(value, columnType[i])
+				continue
+			}
+			if columnType[i] == quote {
+				if i+1 < len(columnType) && columnType[i+1] == quote {
+					i++
+					value = append(value, quote)
+					continue
+				}
+				break
+			}
+			value = append(value, columnType[i])
+		}
+		enum.Values = append(enum.Values, string(value))
+	}
+	if len(enum.Values) == 0 {
+		return nil
+	}
+	return enum
+}
+
+// identifier Replace the characters which can not be used in go identifier with underline.
+func identifier(name string) string {
+	tmp := []byte(name)
+	for i, c := range tmp {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			tmp[i] = '_'
+		}
+	}
+	return string(tmp)
 }
 
 func (s *SchemaColumn) databaseTypeToGoType() (types string) {
@@ -663,20 +1893,56 @@ func (s *SchemaColumn) databaseTypeToGoType() (types string) {
 	if s.IsNullable != nil && strings.ToLower(*s.IsNullable) == "no" {
 		nullable = false
 	}
+	if mapping := s.typeMapping(); mapping != nil {
+		if !nullable {
+			return mapping.GoType
+		}
+		if mapping.GoTypeNullable != "" {
+			return mapping.GoTypeNullable
+		}
+		return "*" + mapping.GoType
+	}
 	datatype := ""
 	if s.DataType != nil {
 		datatype = strings.ToLower(*s.DataType)
 	}
+	unsigned := s.ColumnType != nil && strings.Contains(strings.ToLower(*s.ColumnType), "unsigned") // mysql
 	switch datatype {
 	case "tinyint":
 		types = "int8"
 	case "smallint", "smallserial":
 		types = "int16"
+	case "mediumint": // mysql
+		types = "int32"
 	case "integer", "serial", "int":
 		types = "int"
+		if unsigned {
+			types = "uint32"
+		}
 	case "bigint", "bigserial":
 		types = "int64"
-	case "decimal", "numeric", "real", "double precision", "double", "float":
+	case "year": // mysql
+		types = "int16"
+	case "bit": // mysql, the bit and bit varying of postgresql are scanned as string, like: 0101
+		types = "string"
+		if s.table != nil && s.table.app.cfg.Driver == hey.DriverNameMysql {
+			types = "BIT"
+		}
+	case "decimal", "numeric":
+		types = s.decimalType()
+	case "money": // postgresql
+		types = s.decimalType()
+		if types == "float64" {
+			// the money is formatted with currency symbol, like: $1,234.56
+			types = "DECIMAL"
+		}
+	case "inet": // postgresql
+		types = "INET"
+	case "cidr": // postgresql
+		types = "CIDR"
+	case "interval": // postgresql
+		types = "INTERVAL"
+	case "real", "double precision", "double", "float":
 		types = "float64"
 	case "char", "character", "character varying", "text", "varchar", "enum", "mediumtext", "longtext":
 		types = "string"
@@ -686,17 +1952,247 @@ func (s *SchemaColumn) databaseTypeToGoType() (types string) {
 		"blob",  // mysql && sqlite
 		"bytea": // postgresql
 		types = "[]byte"
+	case "array": // postgresql
+		types = s.arrayType()
+	case "json", "jsonb":
+		types = "json.RawMessage"
 	default:
 		types = "string"
 	}
+	if unsigned && strings.HasPrefix(types, "int") {
+		// tinyint unsigned => uint8
+		types = "u" + types
+	}
+	if s.isTime() {
+		switch s.table.app.cfg.TimeType {
+		case TimeTypeTime:
+			types = "time.Time"
+		case TimeTypeNull:
+			types = "time.Time"
+			if nullable {
+				return "sql.NullTime"
+			}
+		}
+	}
+	if s.enum != nil {
+		types = s.enum.goName()
+		if s.enum.Set {
+			types = s.enum.setName()
+		}
+	}
+	if s.json != nil {
+		types = s.json.name
+	}
 	if nullable {
-		if types != "[]byte" {
+		// NULL is scanned as nil slice
+		if types != "[]byte" && types != "json.RawMessage" && !strings.HasPrefix(types, "pq.") {
 			types = "*" + types
 		}
 	}
 	return
 }
 
+// arrayType The go type of postgresql array column by the element type, like: _int4 => pq.Int32Array.
+func (s *SchemaColumn) arrayType() string {
+	udtName := ""
+	if s.UdtName != nil {
+		udtName = strings.TrimPrefix(strings.ToLower(*s.UdtName), "_")
+	}
+	switch udtName {
+	case "int2", "int4":
+		return "pq.Int32Array"
+	case "int8":
+		return "pq.Int64Array"
+	case "float4":
+		return "pq.Float32Array"
+	case "float8":
+		return "pq.Float64Array"
+	case "numeric":
+		if s.decimalType() != "float64" {
+			return "pq.StringArray"
+		}
+		return "pq.Float64Array"
+	case "bool":
+		return "pq.BoolArray"
+	case "bytea":
+		return "pq.ByteaArray"
+	}
+	return "pq.StringArray"
+}
+
+// typeMapping The type mapping rule of config matched by the column.
+func (s *SchemaColumn) typeMapping() *TypeMapping {
+	if s.table == nil || s.table.TableName == nil || s.ColumnName == nil {
+		return nil
+	}
+	dataType, columnType := "", ""
+	if s.DataType != nil {
+		dataType = *s.DataType
+	}
+	if s.ColumnType != nil {
+		columnType = *s.ColumnType
+	}
+	return s.table.app.cfg.typeMapping(*s.table.TableName, *s.ColumnName, dataType, columnType)
+}
+
+// goImports The packages need to be imported by the go type of column.
+func (s *SchemaColumn) goImports() []string {
+	if mapping := s.typeMapping(); mapping != nil {
+		return mapping.Import
+	}
+	types := strings.TrimPrefix(s.databaseTypeToGoType(), "*")
+	switch {
+	case strings.HasPrefix(types, "pq."):
+		return []string{"github.com/lib/pq"}
+	case strings.HasPrefix(types, "json."):
+		return []string{"encoding/json"}
+	case s.isDecimal() && types == s.table.app.cfg.DecimalType && s.table.app.cfg.DecimalImport != "":
+		return []string{s.table.app.cfg.DecimalImport}
+	}
+	return nil
+}
+
+// isDecimal Whether the column is decimal, numeric or money type.
+func (s *SchemaColumn) isDecimal() bool {
+	if s.DataType == nil {
+		return false
+	}
+	dataType := strings.ToLower(*s.DataType)
+	return dataType == "decimal" || dataType == "numeric" || dataType == "money"
+}
+
+// decimalType The go type of decimal column by config, like: float64 | DECIMAL | decimal.Decimal.
+func (s *SchemaColumn) decimalType() string {
+	switch s.table.app.cfg.DecimalType {
+	case "", DecimalTypeFloat64:
+		return "float64"
+	case DecimalTypeString:
+		return "DECIMAL"
+	}
+	return s.table.app.cfg.DecimalType
+}
+
+// isGenerated Whether the column is generated by the database and can not be written, like: stored or virtual generated column, GENERATED ALWAYS AS IDENTITY.
+func (s *SchemaColumn) isGenerated() bool {
+	if s.Extra != nil {
+		// the DEFAULT_GENERATED of mysql is the column with expression default value
+		extra := strings.ToUpper(*s.Extra)
+		if strings.Contains(extra, "STORED GENERATED") || strings.Contains(extra, "VIRTUAL GENERATED") {
+			return true
+		}
+	}
+	if s.IsGenerated != nil && strings.ToUpper(*s.IsGenerated) == "ALWAYS" {
+		return true
+	}
+	return s.IsIdentity != nil && strings.ToUpper(*s.IsIdentity) == "YES" &&
+		s.IdentityGeneration != nil && strings.ToUpper(*s.IdentityGeneration) == "ALWAYS"
+}
+
+// isGoTime Whether the go type of column is time.Time or sql.NullTime.
+func (s *SchemaColumn) isGoTime() bool {
+	return s.isTime() && s.typeMapping() == nil && (s.table.app.cfg.TimeType == TimeTypeTime || s.table.app.cfg.TimeType == TimeTypeNull)
+}
+
+// isTime Whether the column is date or time type, the time of mysql is not included which may be out of a day.
+func (s *SchemaColumn) isTime() bool {
+	if s.DataType == nil {
+		return false
+	}
+	switch strings.ToLower(*s.DataType) {
+	case "date", "datetime", "timestamp", "timestamptz", "timestamp without time zone", "timestamp with time zone":
+		return true
+	case "time", "timetz", "time without time zone", "time with time zone":
+		return s.table == nil || s.table.app.cfg.Driver != hey.DriverNameMysql
+	}
+	return false
+}
+
+// validate The rules of validate tag after omitempty.
+func (s *SchemaColumn) validate() string {
+	if s.typeMapping() != nil {
+		// the rules may not be suitable for the custom type
+		return ""
+	}
+	opts := ""
+	if s.CharacterMaximumLength != nil && *s.CharacterOctetLength > 0 {
+		opts = fmt.Sprintf(",min=0,max=%d", *s.CharacterMaximumLength)
+	}
+	if s.enum != nil {
+		if oneof := s.enum.oneof(); oneof != "" {
+			opts = fmt.Sprintf("%s,oneof=%s", opts, oneof)
+		}
+	}
+	if s.DataType != nil {
+		switch strings.ToLower(*s.DataType) {
+		case "uuid": // postgresql
+			opts += ",uuid"
+		case "inet": // postgresql
+			opts += ",ip|cidr" // like: 192.168.0.1 or 192.168.0.1/24
+		case "cidr": // postgresql
+			opts += ",cidr"
+		case "macaddr", "macaddr8": // postgresql
+			opts += ",mac"
+		}
+	}
+	if s.isDecimal() && s.NumericPrecision != nil && *s.NumericPrecision > 0 {
+		scale := 0
+		if s.NumericScale != nil && *s.NumericScale > 0 && *s.NumericScale <= *s.NumericPrecision {
+			scale = *s.NumericScale
+		}
+		switch s.decimalType() {
+		case "float64":
+			// numeric(5,2) => [-999.99, 999.99]
+			limit := strings.Repeat("9", *s.NumericPrecision-scale)
+			if limit == "" {
+				limit = "0"
+			}
+			if scale > 0 {
+				limit = fmt.Sprintf("%s.%s", limit, strings.Repeat("9", scale))
+			}
+			opts = fmt.Sprintf("%s,min=-%s,max=%s", opts, limit, limit)
+		case "DECIMAL":
+			// sign, digits and point
+			length := *s.NumericPrecision + 1
+			if scale > 0 {
+				length++
+			}
+			opts = fmt.Sprintf("%s,numeric,max=%d", opts, length)
+		}
+	}
+	return validateBounds(opts + s.check)
+}
+
+// validateBounds Merge the repeated min and max options, the tighter value is kept, like: ,min=0,max=20,max=10 => ,min=0,max=10
+func validateBounds(opts string) string {
+	items := strings.Split(opts, ",")
+	index := make(map[string]int, 2)
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		key, value, ok := strings.Cut(item, "=")
+		if !ok || (key != "min" && key != "max") {
+			result = append(result, item)
+			continue
+		}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(result)
+			result = append(result, item)
+			continue
+		}
+		_, prev, _ := strings.Cut(result[i], "=")
+		a, errA := strconv.ParseFloat(prev, 64)
+		b, errB := strconv.ParseFloat(value, 64)
+		if errA != nil || errB != nil {
+			result = append(result, item)
+			continue
+		}
+		if (key == "min" && b > a) || (key == "max" && b < a) {
+			result[i] = item
+		}
+	}
+	return strings.Join(result, ",")
+}
+
 func (s *SchemaColumn) pascal() string {
 	return utils.Pascal(*s.ColumnName)
 }
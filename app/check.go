@@ -0,0 +1,388 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cd365/hey/v2"
+)
+
+// checkValue The operand of the check constraint, a column, the length of a column or a literal.
+type checkValue struct {
+	column *SchemaColumn // column or the argument of the length function
+	length bool          // char_length(column)
+	value  string        // literal
+	number bool          // the literal is a number
+}
+
+// checkTranslator Translate the check constraints into the validate options of columns.
+type checkTranslator struct {
+	table *SchemaTable
+	mysql bool
+}
+
+// checkValidate Translate the check constraints of the table into the validate options of columns,
+// the constraints that cannot be translated are returned.
+// The supported forms: col > 0, col BETWEEN a AND b, col IN (...), char_length(col) <= n and their conjunctions.
+func (s *SchemaTable) checkValidate() []*SchemaCheck {
+	translator := &checkTranslator{
+		table: s,
+		mysql: s.app.cfg.Driver == hey.DriverNameMysql,
+	}
+	for _, c := range s.Column {
+		c.check = ""
+	}
+	untranslated := make([]*SchemaCheck, 0)
+	for _, v := range s.TableCheck {
+		result, ok := translator.translate(v.CheckClause)
+		if !ok {
+			untranslated = append(untranslated, v)
+			continue
+		}
+		for _, c := range s.Column {
+			if opts, ok := result[c]; ok {
+				c.check += opts
+			}
+		}
+	}
+	return untranslated
+}
+
+// translate The validate options of the columns, ok is false if any part of the clause cannot be translated.
+func (s *checkTranslator) translate(clause string) (result map[*SchemaColumn]string, ok bool) {
+	tokens, err := ddlTokenize(clause, s.mysql)
+	if err != nil {
+		return nil, false
+	}
+	result = make(map[*SchemaColumn]string)
+	for _, item := range s.conjunction(s.normalize(tokens)) {
+		column, opts := s.condition(checkParentheses(item))
+		if column == nil || opts == "" || column.typeMapping() != nil {
+			return nil, false
+		}
+		result[column] += opts
+	}
+	return result, len(result) > 0
+}
+
+// normalize Remove the type casts (::numeric) of postgresql and the charset introducers (_utf8mb4'a') of mysql.
+func (s *checkTranslator) normalize(tokens []*ddlToken) []*ddlToken {
+	result := make([]*ddlToken, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if t.kind == ddlTokenSymbol && t.value == "::" {
+			// ::integer | ::character varying | ::numeric(10,2) | ::text[]
+			for i+1 < len(tokens) && tokens[i+1].kind == ddlTokenWord {
+				i++
+				if next := i + 1; next < len(tokens) && tokens[next].kind == ddlTokenWord && !checkTypeWord(tokens[next].value) {
+					break
+				}
+			}
+			for i+1 < len(tokens) && tokens[i+1].kind == ddlTokenSymbol && (tokens[i+1].value == "(" || tokens[i+1].value == "[") {
+				closed := map[string]string{"(": ")", "[": "]"}[tokens[i+1].value]
+				for i++; i < len(tokens) && tokens[i].value != closed; i++ {
+				}
+			}
+			continue
+		}
+		if s.mysql && t.kind == ddlTokenWord && strings.HasPrefix(t.value, "_") && i+1 < len(tokens) && tokens[i+1].kind == ddlTokenString {
+			continue
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+// checkTypeWord Whether the word is a part of the multi-word type name, like: character varying, timestamp without time zone.
+func checkTypeWord(word string) bool {
+	switch strings.ToLower(word) {
+	case "varying", "precision", "without", "with", "time", "zone":
+		return true
+	}
+	return false
+}
+
+// checkParentheses Remove the parentheses around the whole expression.
+func checkParentheses(tokens []*ddlToken) []*ddlToken {
+	for len(tokens) >= 2 && tokens[0].kind == ddlTokenSymbol && tokens[0].value == "(" && tokens[len(tokens)-1].kind == ddlTokenSymbol && tokens[len(tokens)-1].value == ")" {
+		depth := 0
+		for i, t := range tokens {
+			if t.kind != ddlTokenSymbol {
+				continue
+			}
+			switch t.value {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			}
+			if depth == 0 && i < len(tokens)-1 {
+				return tokens // like: (a) AND (b)
+			}
+		}
+		tokens = tokens[1 : len(tokens)-1]
+	}
+	return tokens
+}
+
+// conjunction Split the expression by the top-level AND, the AND of BETWEEN is kept.
+func (s *checkTranslator) conjunction(tokens []*ddlToken) [][]*ddlToken {
+	tokens = checkParentheses(tokens)
+	result := make([][]*ddlToken, 0, 2)
+	depth, start, between := 0, 0, false
+	for i, t := range tokens {
+		switch {
+		case t.kind == ddlTokenSymbol && t.value == "(":
+			depth++
+		case t.kind == ddlTokenSymbol && t.value == ")":
+			depth--
+		case depth > 0 || t.kind != ddlTokenWord:
+		case strings.EqualFold(t.value, "BETWEEN"):
+			between = true
+		case strings.EqualFold(t.value, "AND") && between:
+			between = false
+		case strings.EqualFold(t.value, "AND"):
+			result = append(result, s.conjunction(tokens[start:i])...)
+			start = i + 1
+		}
+	}
+	if start == 0 {
+		return append(result, tokens)
+	}
+	return append(result, s.conjunction(tokens[start:])...)
+}
+
+// condition Translate a condition like: col > 0 | col BETWEEN 1 AND 9 | col IN (1, 2) | col = ANY (ARRAY[1, 2]) | char_length(col) <= 32
+func (s *checkTranslator) condition(tokens []*ddlToken) (*SchemaColumn, string) {
+	depth := 0
+	for i, t := range tokens {
+		if t.kind == ddlTokenSymbol && t.value == "(" {
+			depth++
+		}
+		if t.kind == ddlTokenSymbol && t.value == ")" {
+			depth--
+		}
+		if depth > 0 || i == 0 {
+			continue
+		}
+		switch {
+		case t.kind == ddlTokenWord && strings.EqualFold(t.value, "BETWEEN"):
+			return s.between(tokens[:i], tokens[i+1:])
+		case t.kind == ddlTokenWord && strings.EqualFold(t.value, "IN"):
+			return s.in(s.value(tokens[:i]), tokens[i+1:])
+		case t.kind == ddlTokenSymbol && strings.Contains("<>=!", t.value):
+			operator, right := t.value, tokens[i+1:]
+			if len(right) > 0 && right[0].kind == ddlTokenSymbol && strings.Contains("<>=", right[0].value) {
+				operator, right = operator+right[0].value, right[1:]
+			}
+			if operator == "=" && len(right) > 0 && right[0].kind == ddlTokenWord && strings.EqualFold(right[0].value, "ANY") {
+				return s.in(s.value(tokens[:i]), right[1:])
+			}
+			return s.compare(s.value(tokens[:i]), operator, s.value(right))
+		}
+	}
+	return nil, ""
+}
+
+// value The operand of the condition, nil if it is not a column, the length of a column or a literal.
+func (s *checkTranslator) value(tokens []*ddlToken) *checkValue {
+	tokens = checkParentheses(tokens)
+	switch {
+	case len(tokens) == 1 && tokens[0].kind == ddlTokenNumber:
+		return &checkValue{value: tokens[0].value, number: true}
+	case len(tokens) == 1 && tokens[0].kind == ddlTokenString:
+		return &checkValue{value: tokens[0].value}
+	case len(tokens) == 1 && (tokens[0].kind == ddlTokenWord || tokens[0].kind == ddlTokenIdent):
+		for _, c := range s.table.Column {
+			if *c.ColumnName == tokens[0].value || (tokens[0].kind == ddlTokenWord && strings.EqualFold(*c.ColumnName, tokens[0].value)) {
+				return &checkValue{column: c}
+			}
+		}
+	case len(tokens) == 2 && tokens[0].kind == ddlTokenSymbol && (tokens[0].value == "-" || tokens[0].value == "+") && tokens[1].kind == ddlTokenNumber:
+		return &checkValue{value: strings.TrimPrefix(tokens[0].value+tokens[1].value, "+"), number: true}
+	case len(tokens) > 3 && tokens[0].kind == ddlTokenWord && tokens[1].kind == ddlTokenSymbol && tokens[1].value == "(":
+		switch strings.ToLower(tokens[0].value) {
+		case "char_length", "character_length":
+		case "length":
+			if s.mysql {
+				return nil // the length of bytes
+			}
+		default:
+			return nil
+		}
+		if tmp := s.value(tokens[2 : len(tokens)-1]); tmp != nil && tmp.column != nil && !tmp.length {
+			tmp.length = true
+			return tmp
+		}
+	}
+	return nil
+}
+
+// values The literals in the parentheses, like: (1, 2, 3) | (ARRAY['a', 'b'])
+func (s *checkTranslator) values(tokens []*ddlToken) []*checkValue {
+	tokens = checkParentheses(tokens)
+	if len(tokens) > 2 && tokens[0].kind == ddlTokenWord && strings.EqualFold(tokens[0].value, "ARRAY") && tokens[1].value == "[" && tokens[len(tokens)-1].value == "]" {
+		tokens = tokens[2 : len(tokens)-1]
+	}
+	result := make([]*checkValue, 0, 4)
+	start := 0
+	for i := 0; i <= len(tokens); i++ {
+		if i < len(tokens) && (tokens[i].kind != ddlTokenSymbol || tokens[i].value != ",") {
+			continue
+		}
+		tmp := s.value(tokens[start:i])
+		if tmp == nil || tmp.column != nil {
+			return nil
+		}
+		result = append(result, tmp)
+		start = i + 1
+	}
+	return result
+}
+
+// compare Translate column OP literal or literal OP column.
+func (s *checkTranslator) compare(left *checkValue, operator string, right *checkValue) (*SchemaColumn, string) {
+	if left == nil || right == nil {
+		return nil, ""
+	}
+	if left.column == nil {
+		// 0 < col => col > 0
+		left, right = right, left
+		if flip, ok := map[string]string{">": "<", ">=": "<=", "<": ">", "<=": ">="}[operator]; ok {
+			operator = flip
+		}
+	}
+	if left.column == nil || right.column != nil {
+		return nil, ""
+	}
+	column := left.column
+	if left.length {
+		// char_length(col) <= 32
+		length, err := strconv.Atoi(right.value)
+		if !right.number || err != nil || checkGoType(column) != "string" {
+			return nil, ""
+		}
+		switch operator {
+		case "<=":
+			return column, fmt.Sprintf(",max=%d", length)
+		case "<":
+			return column, fmt.Sprintf(",max=%d", length-1)
+		case ">=":
+			return column, fmt.Sprintf(",min=%d", length)
+		case ">":
+			return column, fmt.Sprintf(",min=%d", length+1)
+		case "=":
+			return column, fmt.Sprintf(",len=%d", length)
+		}
+		return nil, ""
+	}
+	if checkGoType(column) == "string" && !right.number {
+		switch {
+		case (operator == "<>" || operator == "!=") && right.value == "":
+			return column, ",min=1"
+		case !checkTagValue(right.value):
+		case operator == "=":
+			return column, fmt.Sprintf(",eq=%s", right.value)
+		case operator == "<>" || operator == "!=":
+			return column, fmt.Sprintf(",ne=%s", right.value)
+		}
+		return nil, ""
+	}
+	if !right.number || !checkNumber(column, right.value) {
+		return nil, ""
+	}
+	tag := map[string]string{">": "gt", ">=": "gte", "<": "lt", "<=": "lte", "=": "eq", "<>": "ne", "!=": "ne"}[operator]
+	if tag == "" {
+		return nil, ""
+	}
+	return column, fmt.Sprintf(",%s=%s", tag, right.value)
+}
+
+// between Translate col BETWEEN a AND b.
+func (s *checkTranslator) between(left []*ddlToken, right []*ddlToken) (*SchemaColumn, string) {
+	column := s.value(left)
+	if column == nil || column.column == nil || column.length {
+		return nil, ""
+	}
+	for i, t := range right {
+		if t.kind != ddlTokenWord || !strings.EqualFold(t.value, "AND") {
+			continue
+		}
+		min, max := s.value(right[:i]), s.value(right[i+1:])
+		if min == nil || max == nil || !min.number || !max.number || !checkNumber(column.column, min.value) || !checkNumber(column.column, max.value) {
+			return nil, ""
+		}
+		return column.column, fmt.Sprintf(",gte=%s,lte=%s", min.value, max.value)
+	}
+	return nil, ""
+}
+
+// in Translate col IN (a, b) and col = ANY (ARRAY[a, b]) of postgresql.
+func (s *checkTranslator) in(left *checkValue, right []*ddlToken) (*SchemaColumn, string) {
+	if left == nil || left.column == nil || left.length {
+		return nil, ""
+	}
+	values := s.values(right)
+	if len(values) == 0 {
+		return nil, ""
+	}
+	items := make([]string, 0, len(values))
+	for _, v := range values {
+		switch checkGoType(left.column) {
+		case "string":
+			if v.number || !checkTagValue(v.value) || strings.ContainsAny(v.value, " \t") {
+				return nil, ""
+			}
+		default:
+			if !v.number || !checkNumber(left.column, v.value) {
+				return nil, ""
+			}
+		}
+		items = append(items, v.value)
+	}
+	return left.column, fmt.Sprintf(",oneof=%s", strings.Join(items, " "))
+}
+
+// checkGoType The go type of column without pointer, like: string, int64, float64.
+func checkGoType(column *SchemaColumn) string {
+	return strings.TrimPrefix(column.databaseTypeToGoType(), "*")
+}
+
+// checkNumber Whether the number can be the parameter of the validate option of the column.
+func checkNumber(column *SchemaColumn, value string) bool {
+	switch types := checkGoType(column); {
+	case strings.HasPrefix(types, "int"):
+		_, err := strconv.ParseInt(value, 10, 64)
+		return err == nil
+	case strings.HasPrefix(types, "uint"):
+		_, err := strconv.ParseUint(value, 10, 64)
+		return err == nil
+	case strings.HasPrefix(types, "float"):
+		_, err := strconv.ParseFloat(value, 64)
+		return err == nil
+	}
+	return false
+}
+
+// checkTagValue Whether the string can be the parameter of the validate option.
+func checkTagValue(value string) bool {
+	return value != "" && !strings.ContainsAny(value, ",|\"`\\\n\r")
+}
+
+// checkClause The condition of the check constraint without the CHECK keyword and the outer parentheses, like: price > 0
+func checkClause(clause string, mysql bool) string {
+	clause = strings.TrimSpace(clause)
+	if len(clause) > 5 && strings.EqualFold(clause[:5], "CHECK") {
+		clause = clause[5:]
+	}
+	tokens, err := ddlTokenize(clause, mysql)
+	if err != nil {
+		return strings.TrimSpace(clause)
+	}
+	// CHECK (...) NOT VALID | CHECK (...) NO INHERIT
+	for len(tokens) > 0 && tokens[len(tokens)-1].kind == ddlTokenWord {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return ddlText(clause, checkParentheses(tokens))
+}
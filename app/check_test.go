@@ -0,0 +1,166 @@
+package app
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/cd365/hey/v2"
+)
+
+func TestCheckValidate(t *testing.T) {
+	tables := map[string]string{
+		hey.DriverNamePostgres: "CREATE TABLE product (id int PRIMARY KEY, name varchar(20) NOT NULL, code text NOT NULL, qty int NOT NULL, score real NOT NULL, status text NOT NULL, CONSTRAINT product_check CHECK (%s));",
+		hey.DriverNameMysql:    "CREATE TABLE `product` (`id` int NOT NULL, `name` varchar(20) NOT NULL, `code` text NOT NULL, `qty` int NOT NULL, `score` double NOT NULL, `status` varchar(8) NOT NULL, PRIMARY KEY (`id`), CONSTRAINT `product_check` CHECK (%s));",
+	}
+	cases := []struct {
+		name     string
+		driver   string
+		clause   string
+		check    map[string]string // column => validate options of the check constraint, empty if it cannot be translated
+		validate map[string]string // column => all validate options
+	}{
+		{
+			name:   "greater than",
+			driver: hey.DriverNamePostgres,
+			clause: "qty > 0",
+			check:  map[string]string{"qty": ",gt=0"},
+		},
+		{
+			name:   "literal on the left",
+			driver: hey.DriverNamePostgres,
+			clause: "0 <= qty",
+			check:  map[string]string{"qty": ",gte=0"},
+		},
+		{
+			name:   "negative float",
+			driver: hey.DriverNamePostgres,
+			clause: "score >= -1.5",
+			check:  map[string]string{"score": ",gte=-1.5"},
+		},
+		{
+			name:   "between",
+			driver: hey.DriverNamePostgres,
+			clause: "qty BETWEEN 1 AND 9",
+			check:  map[string]string{"qty": ",gte=1,lte=9"},
+		},
+		{
+			name:   "conjunction",
+			driver: hey.DriverNamePostgres,
+			clause: "(qty > 0) AND (qty < 100) AND (code <> ''::text)",
+			check:  map[string]string{"qty": ",gt=0,lt=100", "code": ",min=1"},
+		},
+		{
+			name:   "any array",
+			driver: hey.DriverNamePostgres,
+			clause: "status = ANY (ARRAY['on'::text, 'off'::text])",
+			check:  map[string]string{"status": ",oneof=on off"},
+		},
+		{
+			name:     "char length merged with varchar length",
+			driver:   hey.DriverNamePostgres,
+			clause:   "char_length((name)::text) <= 10",
+			check:    map[string]string{"name": ",max=10"},
+			validate: map[string]string{"name": ",min=0,max=10"},
+		},
+		{
+			name:     "tighter varchar length is kept",
+			driver:   hey.DriverNamePostgres,
+			clause:   "char_length(name) <= 30",
+			check:    map[string]string{"name": ",max=30"},
+			validate: map[string]string{"name": ",min=0,max=20"},
+		},
+		{
+			name:     "char length minimum",
+			driver:   hey.DriverNamePostgres,
+			clause:   "char_length(name) > 1 AND char_length(name) < 30",
+			check:    map[string]string{"name": ",min=2,max=29"},
+			validate: map[string]string{"name": ",min=2,max=20"},
+		},
+		{
+			name:   "disjunction",
+			driver: hey.DriverNamePostgres,
+			clause: "qty > 0 OR qty = -1",
+		},
+		{
+			name:   "column compared with column",
+			driver: hey.DriverNamePostgres,
+			clause: "qty > score",
+		},
+		{
+			name:   "function of column",
+			driver: hey.DriverNamePostgres,
+			clause: "lower(code) = code",
+		},
+		{
+			name:   "value with space",
+			driver: hey.DriverNamePostgres,
+			clause: "status IN ('on', 'turned off')",
+		},
+		{
+			name:   "string compared as number",
+			driver: hey.DriverNamePostgres,
+			clause: "status > 'a'",
+		},
+		{
+			name:   "partly translatable",
+			driver: hey.DriverNamePostgres,
+			clause: "qty > 0 AND lower(code) = code",
+		},
+		{
+			name:   "mysql greater than",
+			driver: hey.DriverNameMysql,
+			clause: "(`qty` > 0)",
+			check:  map[string]string{"qty": ",gt=0"},
+		},
+		{
+			name:   "mysql in with charset introducer",
+			driver: hey.DriverNameMysql,
+			clause: "(`status` in (_utf8mb4'on',_utf8mb4'off'))",
+			check:  map[string]string{"status": ",oneof=on off"},
+		},
+		{
+			name:   "mysql char length",
+			driver: hey.DriverNameMysql,
+			clause: "(char_length(`name`) >= 3)",
+			check:  map[string]string{"name": ",min=3"},
+		},
+		{
+			name:   "mysql length of bytes",
+			driver: hey.DriverNameMysql,
+			clause: "(length(`name`) <= 10)",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			table := ddlTables(t, c.driver, fmt.Sprintf(tables[c.driver], c.clause))["product"]
+			if table == nil || len(table.TableCheck) != 1 {
+				t.Fatalf("the check constraint of product is not parsed")
+			}
+			untranslated := table.checkValidate()
+			if len(c.check) == 0 && len(untranslated) != 1 {
+				t.Errorf("the check constraint is translated, but it should not be")
+			}
+			if len(c.check) > 0 && len(untranslated) != 0 {
+				t.Errorf("the check constraint is not translated")
+			}
+			check, validate := make(map[string]string), make(map[string]string)
+			for _, v := range table.Column {
+				if v.check != "" {
+					check[*v.ColumnName] = v.check
+				}
+				if _, ok := c.validate[*v.ColumnName]; ok {
+					validate[*v.ColumnName] = v.validate()
+				}
+			}
+			if len(check) > 0 || len(c.check) > 0 {
+				if !reflect.DeepEqual(check, c.check) {
+					t.Errorf("check: got %q, want %q", check, c.check)
+				}
+			}
+			if len(c.validate) > 0 && !reflect.DeepEqual(validate, c.validate) {
+				t.Errorf("validate: got %q, want %q", validate, c.validate)
+			}
+		})
+	}
+}
@@ -5,6 +5,21 @@ import (
 	"gopkg.in/yaml.v3"
 	"os"
 	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	packageRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+const (
+	TimeTypeString = "string"   // 日期时间字段使用 string
+	TimeTypeTime   = "time"     // 日期时间字段使用 time.Time 允许为null的字段使用 *time.Time
+	TimeTypeNull   = "nulltime" // 日期时间字段使用 time.Time 允许为null的字段使用 sql.NullTime
+
+	DecimalTypeFloat64 = "float64" // 定点数字段使用 float64
+	DecimalTypeString  = "string"  // 定点数字段使用生成的以字符串存储的 DECIMAL 类型
 )
 
 type Config struct {
@@ -14,17 +29,32 @@ type Config struct {
 
 	Schema string `json:"schema" yaml:"schema"` // 模板代码中的schema unique value
 
-	Driver         string `json:"driver" yaml:"driver"`                     // 数据库驱动名称 mysql|postgres
-	DataSourceName string `json:"data_source_name" yaml:"data_source_name"` // 数据源地址 mysql=>root:112233@tcp(127.0.0.1:3306)/hello?charset=utf8mb4&collation=utf8mb4_unicode_ci&timeout=90s pgsql=>postgres://postgres:112233@[::1]:5432/hello?sslmode=disable
+	Driver         string `json:"driver" yaml:"driver"`                     // 数据库驱动名称 mysql|postgres|sqlite3
+	DataSourceName string `json:"data_source_name" yaml:"data_source_name"` // 数据源地址 mysql=>root:112233@tcp(127.0.0.1:3306)/hello?charset=utf8mb4&collation=utf8mb4_unicode_ci&timeout=90s pgsql=>postgres://postgres:112233@[::1]:5432/hello?sslmode=disable sqlite3=>file:hello.db?mode=ro
+
+	DdlFiles []string `json:"ddl_files" yaml:"ddl_files"` // 建表语句文件(支持通配符) 未配置数据源地址时从这些文件中解析表结构 如: aaa_table_create.sql
 
-	TableSchemaName      string `json:"table_schema_name" yaml:"table_schema_name"`             // 数据库模式名称 mysql可以使用数据库名,pgsql可以使用schema名称 mysql默认空,pgsql默认public
+	Concurrency  int           `json:"concurrency" yaml:"concurrency"`     // 同时查询数据库的最大数量(同时也是连接池的最大连接数) 默认8
+	Timeout      string        `json:"timeout" yaml:"timeout"`             // 查询数据库表结构的总超时时间 如: 10m 默认不限制
+	QueryTimeout string        `json:"query_timeout" yaml:"query_timeout"` // 单条查询的超时时间 如: 30s 默认不限制
+	timeout      time.Duration // 查询数据库表结构的总超时时间
+	queryTimeout time.Duration // 单条查询的超时时间
+
+	TableSchemaName      string `json:"table_schema_name" yaml:"table_schema_name"`             // 数据库模式名称 mysql可以使用数据库名,pgsql可以使用schema名称,sqlite3可以使用附加数据库名称 mysql默认空,pgsql默认public,sqlite3默认main
 	UsingTableSchemaName bool   `json:"using_table_schema_name" yaml:"using_table_schema_name"` // 是否使用模式名称 在表名之前指定模式名称 如: public.account
 
+	TableSchemaList    []*TableSchema `json:"table_schema_list" yaml:"table_schema_list"` // 多个数据库模式 配置后忽略 TableSchemaName 如: public,billing,audit
+	ImportPath         string         `json:"import_path" yaml:"import_path"`             // 包 Package 的导入路径 模式输出到独立的包时顶层 Database 需要引用这些包 不配置时根据 go.mod 推断
+	tableSchemaPrefix  string         // 当前模式的类型名称前缀
+	tableSchemaDefault string         // 未指定模式名称的表所属的模式(从建表语句文件中解析表结构时使用)
+
 	ColumnSerial    string `json:"column_serial" yaml:"column_serial"`         // 表的序号字段(自动递增的字段) 数据库表本身应该具有唯一字段名 只能设置一个字段 通常是 id
 	ColumnCreatedAt string `json:"column_created_at" yaml:"column_created_at"` // 表数据创建时间标记字段 通常是int或者int64类型 多个使用','隔开
 	ColumnUpdatedAt string `json:"column_updated_at" yaml:"column_updated_at"` // 表数据更新时间标记字段 通常是int或者int64类型 多个使用','隔开
 	ColumnDeletedAt string `json:"column_deleted_at" yaml:"column_deleted_at"` // 表数据伪删除时间标记字段 通常是int或者int64类型 多个使用','隔开
 
+	TimeType string `json:"time_type" yaml:"time_type"` // 日期时间字段的类型 string(默认)|time|nulltime 使用time或nulltime时mysql数据源需要设置parseTime=true 日期时间类型的创建,更新,伪删除时间标记字段写入 time.Time
+
 	Package                 string `json:"package" yaml:"package"`                                     // 包名
 	TemplateOutputDirectory string `json:"template_output_directory" yaml:"template_output_directory"` // 模板文件输出路径
 
@@ -35,10 +65,128 @@ type Config struct {
 	AllowTableNameMatchRules []string         `json:"allow_table_name_match_rules" yaml:"allow_table_name_match_rules"` // 满足禁止构建中的某一条正则,但是又满足当前允许构建中的某一条正则 (优先级高于 DisableTableNameMatchRules)
 	allowTableNameMatchRules []*regexp.Regexp // 允许构建表的正则表达式 表名称只需要满足其中一条正则表达式即可 不配置即无效 (AllowTableName 和 AllowTableNameMatchRules 可搭配使用, AllowTableName 优先使用)
 
+	DecimalType   string `json:"decimal_type" yaml:"decimal_type"`     // 定点数(decimal,numeric)字段的类型 float64(默认)|string|自定义类型 如: decimal.Decimal
+	DecimalImport string `json:"decimal_import" yaml:"decimal_import"` // 自定义定点数类型所在包的导入路径 如: github.com/shopspring/decimal
+
+	ColumnJsonType []*ColumnJsonType `json:"column_json_type" yaml:"column_json_type"` // JSON字段绑定的自定义类型 未绑定的JSON字段使用 json.RawMessage
+
+	TypeMapping []*TypeMapping `json:"type_mapping" yaml:"type_mapping"` // 字段类型映射 按配置顺序匹配第一条满足的规则 优先于内置的类型映射
+
 	DatabaseIdentify string `json:"-" yaml:"-"` // 数据库标识符号 mysql: ` postgres: "
 }
 
+// TypeMapping 字段类型映射 DatabaseType, Column, ColumnRegexp 至少配置一个 配置多个时需要同时满足
+type TypeMapping struct {
+	DatabaseType   string   `json:"database_type" yaml:"database_type"`       // 数据库类型 匹配 data_type 或者 column_type 不区分大小写 如: uuid | tinyint(1)
+	Column         string   `json:"column" yaml:"column"`                     // 字段 表名.字段名 如: account.balance
+	ColumnRegexp   string   `json:"column_regexp" yaml:"column_regexp"`       // 字段名称的正则表达式 如: ^is_
+	GoType         string   `json:"go_type" yaml:"go_type"`                   // go类型 如: uuid.UUID
+	GoTypeNullable string   `json:"go_type_nullable" yaml:"go_type_nullable"` // 允许为null的字段的go类型 不配置时使用 GoType 的指针类型 如: uuid.NullUUID
+	Import         []string `json:"import" yaml:"import"`                     // 类型需要导入的包 如: github.com/google/uuid
+
+	columnRegexp *regexp.Regexp
+}
+
+// match Whether the column matches the rule.
+func (s *TypeMapping) match(table string, column string, dataType string, columnType string) bool {
+	if s.DatabaseType != "" && !strings.EqualFold(s.DatabaseType, dataType) && !strings.EqualFold(s.DatabaseType, columnType) {
+		return false
+	}
+	if s.Column != "" && s.Column != table+"."+column {
+		return false
+	}
+	if s.columnRegexp != nil && !s.columnRegexp.MatchString(column) {
+		return false
+	}
+	return true
+}
+
+// ColumnJsonType JSON字段绑定的自定义类型 生成实现 sql.Scanner 和 driver.Valuer 的包装类型
+type ColumnJsonType struct {
+	Column string `json:"column" yaml:"column"` // 字段 表名.字段名 如: account.profile
+	Import string `json:"import" yaml:"import"` // 类型所在包的导入路径 内置类型不需要配置 如: github.com/cd365/example/types
+	Type   string `json:"type" yaml:"type"`     // 类型名称 如: types.Profile | map[string]interface{}
+}
+
+// TableSchema 数据库模式
+type TableSchema struct {
+	Name    string `json:"name" yaml:"name"`       // 模式名称 如: billing
+	Package string `json:"package" yaml:"package"` // 包名 输出到 Package 目录下的同名子目录 不配置时输出到 Package 中
+	Prefix  string `json:"prefix" yaml:"prefix"`   // 类型名称前缀 输出到同一个包中的多个模式使用不同的前缀区分同名表 如: Billing
+}
+
 func (s *Config) Initial() error {
+	names := make(map[string]*struct{})
+	prefixes := make(map[string]*struct{})
+	for _, v := range s.TableSchemaList {
+		if v == nil || v.Name == "" {
+			return fmt.Errorf("table schema name is empty")
+		}
+		if _, ok := names[v.Name]; ok {
+			return fmt.Errorf("duplicate table schema name: %s", v.Name)
+		}
+		names[v.Name] = &struct{}{}
+		if v.Package != "" && !packageRegexp.MatchString(v.Package) {
+			return fmt.Errorf("invalid package name of table schema %s: %s", v.Name, v.Package)
+		}
+		if v.Package != "" && v.Package != s.Package {
+			continue
+		}
+		// schemas in the same package are distinguished by prefix
+		if _, ok := prefixes[v.Prefix]; ok {
+			return fmt.Errorf("the schemas output to package %s require different prefixes: %s", s.Package, v.Name)
+		}
+		prefixes[v.Prefix] = &struct{}{}
+	}
+	if s.Concurrency < 0 {
+		return fmt.Errorf("invalid concurrency: %d", s.Concurrency)
+	}
+	if s.Concurrency == 0 {
+		s.Concurrency = 8
+	}
+	for _, v := range []struct {
+		value  string
+		result *time.Duration
+	}{
+		{s.Timeout, &s.timeout},
+		{s.QueryTimeout, &s.queryTimeout},
+	} {
+		if v.value == "" {
+			continue
+		}
+		tmp, err := time.ParseDuration(v.value)
+		if err != nil || tmp < 0 {
+			return fmt.Errorf("invalid timeout: %s", v.value)
+		}
+		*v.result = tmp
+	}
+	switch s.TimeType {
+	case "", TimeTypeString, TimeTypeTime, TimeTypeNull:
+	default:
+		return fmt.Errorf("unsupported time type: %s", s.TimeType)
+	}
+	columns := make(map[string]*struct{})
+	for _, v := range s.ColumnJsonType {
+		if v == nil || strings.Count(v.Column, ".") != 1 || v.Type == "" {
+			return fmt.Errorf("invalid column json type, the column should be like table.column and the type is required")
+		}
+		if _, ok := columns[v.Column]; ok {
+			return fmt.Errorf("duplicate column json type: %s", v.Column)
+		}
+		columns[v.Column] = &struct{}{}
+	}
+	for _, v := range s.TypeMapping {
+		if v == nil || v.GoType == "" || v.DatabaseType == "" && v.Column == "" && v.ColumnRegexp == "" {
+			return fmt.Errorf("invalid type mapping, the go type and one of database type, column, column regexp are required")
+		}
+		if v.ColumnRegexp != "" {
+			tmpRegexp, err := regexp.Compile(v.ColumnRegexp)
+			if err != nil {
+				return err
+			}
+			v.columnRegexp = tmpRegexp
+		}
+	}
 	for _, v := range s.DisableTableNameMatchRules {
 		tmpRegexp, err := regexp.Compile(v)
 		if err != nil {
@@ -56,6 +204,34 @@ func (s *Config) Initial() error {
 	return nil
 }
 
+// columnJsonType The custom type bound to the json column.
+func (s *Config) columnJsonType(table string, column string) *ColumnJsonType {
+	for _, v := range s.ColumnJsonType {
+		if v.Column == table+"."+column {
+			return v
+		}
+	}
+	return nil
+}
+
+// typeMapping The first type mapping rule matched by the column.
+func (s *Config) typeMapping(table string, column string, dataType string, columnType string) *TypeMapping {
+	for _, v := range s.TypeMapping {
+		if v.match(table, column, dataType, columnType) {
+			return v
+		}
+	}
+	return nil
+}
+
+// schemaPackage The package name of the schema.
+func (s *Config) schemaPackage(schema *TableSchema) string {
+	if schema.Package == "" {
+		return s.Package
+	}
+	return schema.Package
+}
+
 func (s *Config) Disable(table string) bool {
 	if s.disableTableNameMatchRules == nil {
 		return false
@@ -89,6 +265,9 @@ func InitConfig(configFile string) error {
 		Schema:                  "S000001",
 		Driver:                  "postgres",
 		DataSourceName:          "postgres://postgres:112233@[::1]:5432/hello?sslmode=disable",
+		Concurrency:             8,
+		Timeout:                 "10m",
+		QueryTimeout:            "1m",
 		TableSchemaName:         "public",
 		UsingTableSchemaName:    true,
 		ColumnSerial:            "id",
@@ -0,0 +1,1733 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cd365/hey/v2"
+)
+
+const (
+	ddlTokenWord   = iota + 1 // keyword or unquoted identifier
+	ddlTokenIdent             // quoted identifier
+	ddlTokenString            // string literal
+	ddlTokenNumber            // number literal
+	ddlTokenSymbol            // punctuation
+)
+
+var (
+	ddlCreateTableReplace    = regexp.MustCompile(`(?i)^CREATE\s+TABLE\s+(IF\s+NOT\s+EXISTS\s+)?`)
+	ddlCreateIndexReplace    = regexp.MustCompile(`(?i)^CREATE\s+(UNIQUE\s+)?INDEX\s+(CONCURRENTLY\s+)?(IF\s+NOT\s+EXISTS\s+)?`)
+	ddlCreateSequenceReplace = regexp.MustCompile(`(?i)^CREATE\s+SEQUENCE\s+(IF\s+NOT\s+EXISTS\s+)?`)
+	ddlPartitionComment      = regexp.MustCompile(`^(/\*!\d*\s*)(?i:PARTITION\s)`)
+	ddlNextval               = regexp.MustCompile(`(?i)nextval\('"?([A-Za-z0-9_."]+?)"?'(::regclass)?\)`)
+)
+
+// ddlToken A lexical unit of the DDL source.
+type ddlToken struct {
+	kind  int
+	value string // word, ident, number, symbol: the text; string: the unescaped content
+	start int    // start offset in source
+	end   int    // end offset in source
+}
+
+// ddlStatement A statement separated by ';'.
+type ddlStatement struct {
+	tokens []*ddlToken
+	raw    string
+	src    string // the whole source, offsets of tokens are based on it
+}
+
+// ddlTokenize Split the source into tokens, comments are discarded.
+func ddlTokenize(src string, mysql bool) ([]*ddlToken, error) {
+	tokens := make([]*ddlToken, 0, 1024)
+	length := len(src)
+	quoted := func(i int, quote byte, backslash bool) (string, int, error) {
+		b := &strings.Builder{}
+		for j := i + 1; j < length; j++ {
+			c := src[j]
+			if backslash && c == '\\' && j+1 < length {
+				j++
+				switch src[j] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				case 'r':
+					b.WriteByte('\r')
+				case '0':
+					b.WriteByte(0)
+				default:
+					b.WriteByte(src[j])
+				}
+				continue
+			}
+			if c == quote {
+				if j+1 < length && src[j+1] == quote {
+					b.WriteByte(quote)
+					j++
+					continue
+				}
+				return b.String(), j + 1, nil
+			}
+			b.WriteByte(c)
+		}
+		return "", 0, fmt.Errorf("unterminated quoted text at offset %d", i)
+	}
+	isWord := func(c byte) bool {
+		return c == '_' || c == '$' || c >= 0x80 || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+	}
+	executable := false // inside the executable comment of mysql /*!50100 ... */
+	for i := 0; i < length; {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+		case mysql && !executable && c == '/' && ddlPartitionComment.MatchString(src[i:]):
+			// the partitioning clause of SHOW CREATE TABLE is executed by mysql, like: /*!50100 PARTITION BY RANGE (id) (...) */
+			executable = true
+			i += len(ddlPartitionComment.FindStringSubmatch(src[i:])[1])
+		case executable && c == '*' && i+1 < length && src[i+1] == '/':
+			executable = false
+			tokens = append(tokens, &ddlToken{kind: ddlTokenSymbol, value: "*/", start: i, end: i + 2})
+			i += 2
+		case c == '-' && i+1 < length && src[i+1] == '-', mysql && c == '#':
+			for i < length && src[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < length && src[i+1] == '*':
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment at offset %d", i)
+			}
+			i += end + 4
+		case c == '\'':
+			value, next, err := quoted(i, '\'', mysql)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, &ddlToken{kind: ddlTokenString, value: value, start: i, end: next})
+			i = next
+		case c == '"' && mysql:
+			value, next, err := quoted(i, '"', true)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, &ddlToken{kind: ddlTokenString, value: value, start: i, end: next})
+			i = next
+		case c == '"' || c == '`':
+			value, next, err := quoted(i, c, false)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, &ddlToken{kind: ddlTokenIdent, value: value, start: i, end: next})
+			i = next
+		case c == '$' && !mysql:
+			// dollar quoted string $$...$$ or $tag$...$tag$
+			end := strings.IndexByte(src[i+1:], '$')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated dollar quoted text at offset %d", i)
+			}
+			tag := src[i : i+end+2]
+			closed := strings.Index(src[i+len(tag):], tag)
+			if closed < 0 {
+				return nil, fmt.Errorf("unterminated dollar quoted text at offset %d", i)
+			}
+			next := i + len(tag) + closed + len(tag)
+			tokens = append(tokens, &ddlToken{kind: ddlTokenString, value: src[i+len(tag) : next-len(tag)], start: i, end: next})
+			i = next
+		case c >= '0' && c <= '9':
+			j := i
+			for j < length && (src[j] >= '0' && src[j] <= '9' || src[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, &ddlToken{kind: ddlTokenNumber, value: src[i:j], start: i, end: j})
+			i = j
+		case isWord(c):
+			j := i
+			for j < length && isWord(src[j]) {
+				j++
+			}
+			if (src[i:j] == "E" || src[i:j] == "e") && j < length && src[j] == '\'' {
+				// postgresql escape string E'...'
+				value, next, err := quoted(j, '\'', true)
+				if err != nil {
+					return nil, err
+				}
+				tokens = append(tokens, &ddlToken{kind: ddlTokenString, value: value, start: i, end: next})
+				i = next
+				continue
+			}
+			tokens = append(tokens, &ddlToken{kind: ddlTokenWord, value: src[i:j], start: i, end: j})
+			i = j
+		case c == ':' && i+1 < length && src[i+1] == ':':
+			tokens = append(tokens, &ddlToken{kind: ddlTokenSymbol, value: "::", start: i, end: i + 2})
+			i += 2
+		default:
+			tokens = append(tokens, &ddlToken{kind: ddlTokenSymbol, value: src[i : i+1], start: i, end: i + 1})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// ddlSplitStatement Split tokens into statements.
+func ddlSplitStatement(src string, tokens []*ddlToken) []*ddlStatement {
+	result := make([]*ddlStatement, 0, 32)
+	start := 0
+	for i, t := range tokens {
+		if t.kind != ddlTokenSymbol || t.value != ";" {
+			continue
+		}
+		if i > start {
+			result = append(result, &ddlStatement{tokens: tokens[start:i], raw: src[tokens[start].start:tokens[i-1].end], src: src})
+		}
+		start = i + 1
+	}
+	if start < len(tokens) {
+		result = append(result, &ddlStatement{tokens: tokens[start:], raw: src[tokens[start].start:tokens[len(tokens)-1].end], src: src})
+	}
+	return result
+}
+
+// ddlCursor Read tokens one by one.
+type ddlCursor struct {
+	tokens []*ddlToken
+	index  int
+	lower  bool // fold unquoted identifiers to lower case (postgresql)
+}
+
+func (s *ddlCursor) eof() bool {
+	return s.index >= len(s.tokens)
+}
+
+func (s *ddlCursor) peek() *ddlToken {
+	if s.eof() {
+		return nil
+	}
+	return s.tokens[s.index]
+}
+
+func (s *ddlCursor) next() *ddlToken {
+	t := s.peek()
+	if t != nil {
+		s.index++
+	}
+	return t
+}
+
+// is Whether the following tokens are the specified words or symbols in turn, ignore case.
+func (s *ddlCursor) is(words ...string) bool {
+	for i, w := range words {
+		if s.index+i >= len(s.tokens) {
+			return false
+		}
+		t := s.tokens[s.index+i]
+		if t.kind != ddlTokenWord && t.kind != ddlTokenSymbol {
+			return false
+		}
+		if !strings.EqualFold(t.value, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// accept If the following tokens are the specified words, consume them.
+func (s *ddlCursor) accept(words ...string) bool {
+	if !s.is(words...) {
+		return false
+	}
+	s.index += len(words)
+	return true
+}
+
+// indexClause Whether the following tokens are an index clause of mysql, like: KEY idx (a) | INDEX USING BTREE (a) | FULLTEXT KEY ft (a).
+// The KEY and INDEX are not reserved words of postgresql, a column definition like: key uuid PRIMARY KEY | index varchar(32) is not an index clause.
+func (s *ddlCursor) indexClause() bool {
+	i := s.index
+	switch {
+	case s.is("FULLTEXT"), s.is("SPATIAL"):
+		i++
+		if t := s.at(i); t != nil && t.kind == ddlTokenWord && (strings.EqualFold(t.value, "KEY") || strings.EqualFold(t.value, "INDEX")) {
+			i++
+		}
+	case s.is("KEY"), s.is("INDEX"):
+		i++
+	default:
+		return false
+	}
+	keys := func(i int) bool {
+		t := s.at(i)
+		switch {
+		case t == nil:
+			return false
+		case t.kind == ddlTokenWord && strings.EqualFold(t.value, "USING"):
+			return true
+		case t.kind != ddlTokenSymbol || t.value != "(":
+			return false
+		}
+		// the length of type, like: varchar(32) | numeric(10,2)
+		next := s.at(i + 1)
+		return next == nil || next.kind != ddlTokenNumber
+	}
+	if keys(i) {
+		return true
+	}
+	t := s.at(i)
+	return t != nil && (t.kind == ddlTokenWord || t.kind == ddlTokenIdent) && keys(i+1)
+}
+
+// at The token at the index, nil if it is out of range.
+func (s *ddlCursor) at(index int) *ddlToken {
+	if index < 0 || index >= len(s.tokens) {
+		return nil
+	}
+	return s.tokens[index]
+}
+
+// ident Read an identifier.
+func (s *ddlCursor) ident() string {
+	t := s.next()
+	if t == nil {
+		return ""
+	}
+	if t.kind == ddlTokenWord && s.lower {
+		return strings.ToLower(t.value)
+	}
+	return t.value
+}
+
+// name Read an object name which may be qualified by schema.
+func (s *ddlCursor) name() (schema string, name string) {
+	name = s.ident()
+	for s.accept(".") {
+		schema, name = name, s.ident()
+	}
+	return
+}
+
+// group Read the tokens in the parentheses and split them by top-level commas.
+func (s *ddlCursor) group() [][]*ddlToken {
+	if !s.accept("(") {
+		return nil
+	}
+	result := make([][]*ddlToken, 0, 8)
+	depth, start := 0, s.index
+	for !s.eof() {
+		t := s.next()
+		if t.kind != ddlTokenSymbol {
+			continue
+		}
+		switch t.value {
+		case "(":
+			depth++
+		case ")":
+			if depth == 0 {
+				if s.index-1 > start {
+					result = append(result, s.tokens[start:s.index-1])
+				}
+				return result
+			}
+			depth--
+		case ",":
+			if depth == 0 {
+				result = append(result, s.tokens[start:s.index-1])
+				start = s.index
+			}
+		}
+	}
+	return result
+}
+
+// skip Skip a token, the parentheses are skipped as a whole.
+func (s *ddlCursor) skip() {
+	if s.is("(") {
+		s.group()
+		return
+	}
+	s.next()
+}
+
+// columns Read the column list like (`a`, `b`(10), c DESC).
+func (s *ddlCursor) columns() []string {
+	result := make([]string, 0, 2)
+	for _, item := range s.group() {
+		tmp := &ddlCursor{tokens: item, lower: s.lower}
+		if t := tmp.peek(); t != nil && (t.kind == ddlTokenWord || t.kind == ddlTokenIdent) {
+			result = append(result, tmp.ident())
+		}
+	}
+	return result
+}
+
+// keyColumns Read the column list of key or index, expression is true if any item is an expression or a prefix of column.
+// The indexed columns omit the expressions and keep the prefix columns of mysql.
+func (s *ddlCursor) keyColumns() (columns []string, expression bool, indexed []string) {
+	columns, indexed = make([]string, 0, 2), make([]string, 0, 2)
+	for _, item := range s.group() {
+		tmp := &ddlCursor{tokens: item, lower: s.lower}
+		t := tmp.peek()
+		if t == nil || (t.kind != ddlTokenWord && t.kind != ddlTokenIdent) {
+			expression = true
+			continue
+		}
+		name := tmp.ident()
+		columns = append(columns, name)
+		if !tmp.is("(") {
+			indexed = append(indexed, name)
+			continue
+		}
+		expression = true // lower(email) | email(10)
+		if group := tmp.group(); !s.lower && len(group) == 1 && len(group[0]) == 1 && group[0][0].kind == ddlTokenNumber {
+			indexed = append(indexed, name)
+		}
+	}
+	return
+}
+
+// HelperDdl Read table structures from DDL files instead of a live database.
+type HelperDdl struct {
+	app       *App
+	tables    []*SchemaTable
+	tableMap  map[string]*SchemaTable
+	tableDdl  map[*SchemaTable][]string
+	sequences map[string]string      // sequence name => CREATE SEQUENCE statement
+	search    string                 // current schema, switched by USE or SET search_path
+	enums     map[string]*SchemaEnum // enum type name => enum type (postgresql)
+	enumDdl   map[string]struct{}    // enum types which are already output in the DDL
+
+	partitionMap   map[string]*SchemaPartition // partition name => partition (postgresql)
+	partitionTable map[string]*SchemaTable     // partition name => the partitioned table at the top (postgresql)
+}
+
+// ddlText The source text of the tokens.
+func ddlText(src string, tokens []*ddlToken) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+	return src[tokens[0].start:tokens[len(tokens)-1].end]
+}
+
+func NewDdl(app *App) Helper {
+	return &HelperDdl{
+		app:       app,
+		tableMap:  make(map[string]*SchemaTable),
+		tableDdl:  make(map[*SchemaTable][]string),
+		sequences: make(map[string]string),
+		enums:     make(map[string]*SchemaEnum),
+		enumDdl:   make(map[string]struct{}),
+
+		partitionMap:   make(map[string]*SchemaPartition),
+		partitionTable: make(map[string]*SchemaTable),
+	}
+}
+
+func (s *HelperDdl) mysql() bool {
+	return s.app.cfg.Driver == hey.DriverNameMysql
+}
+
+func (s *HelperDdl) postgres() bool {
+	return s.app.cfg.Driver == hey.DriverNamePostgres
+}
+
+// files Expand the glob patterns of DDL files.
+func (s *HelperDdl) files() ([]string, error) {
+	result := make([]string, 0, len(s.app.cfg.DdlFiles))
+	exists := make(map[string]*struct{})
+	for _, pattern := range s.app.cfg.DdlFiles {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("ddl file does not exist: %s", pattern)
+		}
+		sort.Strings(matches)
+		for _, v := range matches {
+			if _, ok := exists[v]; ok {
+				continue
+			}
+			exists[v] = &struct{}{}
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
+
+func (s *HelperDdl) QueryAllTable(ctx context.Context) error {
+	files, err := s.files()
+	if err != nil {
+		return err
+	}
+	for _, filename := range files {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		s.search = ""
+		src := string(content)
+		tokens, err := ddlTokenize(src, s.mysql())
+		if err != nil {
+			return fmt.Errorf("%s: %s", filename, err.Error())
+		}
+		for _, stmt := range ddlSplitStatement(src, tokens) {
+			if err = s.statement(stmt); err != nil {
+				return fmt.Errorf("%s: %s", filename, err.Error())
+			}
+		}
+	}
+	sort.Slice(s.tables, func(i, j int) bool { return *s.tables[i].TableName < *s.tables[j].TableName })
+	// columns of enum types, the type may be created after the table
+	for _, table := range s.tables {
+		for _, c := range table.Column {
+			if c.ColumnType == nil {
+				continue
+			}
+			if s.mysql() {
+				c.enum = columnEnum(c)
+				continue
+			}
+			if enum, ok := s.enums[*c.ColumnType]; ok {
+				dataType := "USER-DEFINED"
+				c.DataType, c.UdtSchema, c.UdtName, c.enum = &dataType, &enum.EnumSchema, &enum.EnumName, enum
+			}
+		}
+	}
+	return nil
+}
+
+// createType CREATE TYPE name AS ENUM ( 'a', 'b' ), other types are ignored.
+func (s *HelperDdl) createType(c *ddlCursor) {
+	schema, name := c.name()
+	if !c.accept("AS", "ENUM") {
+		return
+	}
+	enum := &SchemaEnum{EnumSchema: schema, EnumName: name}
+	if schema == "" {
+		enum.EnumSchema = s.search
+	}
+	if s.schema(schema) {
+		enum.EnumSchema = s.app.cfg.TableSchemaName
+		enum.prefix = s.app.cfg.tableSchemaPrefix
+	}
+	for _, item := range c.group() {
+		if len(item) == 1 && item[0].kind == ddlTokenString {
+			enum.Values = append(enum.Values, item[0].value)
+		}
+	}
+	s.enums[name] = enum
+}
+
+// table Get the table by name, tables of other schemas are ignored.
+func (s *HelperDdl) table(schema string, name string) *SchemaTable {
+	if !s.schema(schema) {
+		return nil
+	}
+	return s.tableMap[name]
+}
+
+// schema Whether the object of the schema belongs to the configured schema.
+func (s *HelperDdl) schema(schema string) bool {
+	if schema == "" {
+		schema = s.search
+	}
+	if schema == "" {
+		schema = s.app.cfg.tableSchemaDefault
+	}
+	return schema == "" || s.app.cfg.TableSchemaName == "" || schema == s.app.cfg.TableSchemaName
+}
+
+func (s *HelperDdl) statement(stmt *ddlStatement) error {
+	c := &ddlCursor{tokens: stmt.tokens, lower: s.postgres()}
+	switch {
+	case c.accept("CREATE"):
+		c.accept("OR", "REPLACE")
+		for c.accept("GLOBAL") || c.accept("LOCAL") || c.accept("TEMPORARY") || c.accept("TEMP") || c.accept("UNLOGGED") {
+		}
+		switch {
+		case c.accept("TABLE"):
+			return s.createTable(c, stmt)
+		case c.is("INDEX"), c.is("UNIQUE", "INDEX"):
+			return s.createIndex(c, stmt)
+		case c.accept("SEQUENCE"):
+			c.accept("IF", "NOT", "EXISTS")
+			schema, name := c.name()
+			if schema != "" {
+				// the sequences of different schemas may have the same name
+				s.sequences[fmt.Sprintf("%s.%s", schema, name)] = stmt.raw
+			}
+			if _, ok := s.sequences[name]; !ok || schema == "" {
+				s.sequences[name] = stmt.raw
+			}
+		case c.accept("TYPE"):
+			s.createType(c)
+		default:
+			// CREATE [ALGORITHM = ...] [DEFINER = ...] [SQL SECURITY ...] [MATERIALIZED | RECURSIVE] VIEW
+			tableType := tableTypeView
+			for !c.eof() && !c.is("VIEW") && !c.is("AS") && !c.is("(") {
+				if c.accept("MATERIALIZED") {
+					tableType = tableTypeMaterializedView
+					continue
+				}
+				c.next()
+			}
+			if c.accept("VIEW") {
+				return s.createView(c, stmt, tableType)
+			}
+		}
+	case c.accept("ALTER", "TABLE"):
+		return s.alterTable(c, stmt)
+	case c.accept("COMMENT", "ON"):
+		return s.commentOn(c, stmt)
+	case c.accept("USE"):
+		s.search = c.ident()
+	case c.accept("SET", "search_path"):
+		if c.accept("TO") || c.accept("=") {
+			if t := c.next(); t != nil && t.kind != ddlTokenSymbol {
+				s.search = t.value
+				if t.kind == ddlTokenWord && c.lower {
+					s.search = strings.ToLower(t.value)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *HelperDdl) createTable(c *ddlCursor, stmt *ddlStatement) error {
+	c.accept("IF", "NOT", "EXISTS")
+	schema, name := c.name()
+	if name == "" {
+		return fmt.Errorf("table name is missing: %s", stmt.raw)
+	}
+	if !s.schema(schema) {
+		return nil
+	}
+	if c.accept("PARTITION", "OF") {
+		return s.partitionOf(c, stmt, name)
+	}
+	if !c.is("(") {
+		return nil // CREATE TABLE ... AS SELECT | CREATE TABLE ... LIKE ...
+	}
+	tableSchema, tableComment := s.app.cfg.TableSchemaName, ""
+	table := &SchemaTable{
+		app:          s.app,
+		TableSchema:  &tableSchema,
+		TableName:    &name,
+		TableComment: &tableComment,
+	}
+	for _, item := range c.group() {
+		tmp := &ddlCursor{tokens: item, lower: c.lower}
+		if s.tableConstraint(tmp, table, stmt.src) {
+			continue
+		}
+		column := s.column(tmp, table, stmt.src)
+		if column != nil {
+			table.Column = append(table.Column, column)
+		}
+	}
+	// table options
+	for !c.eof() {
+		if c.accept("COMMENT") {
+			c.accept("=")
+			if t := c.next(); t != nil {
+				*table.TableComment = t.value
+			}
+			continue
+		}
+		if c.accept("PARTITION", "BY") {
+			table.PartitionKey = s.partitionKey(c, stmt.src)
+			table.Partitions = s.partitions(c, stmt.src)
+			continue
+		}
+		c.skip()
+	}
+	if exists, ok := s.tableMap[name]; ok {
+		for i, v := range s.tables {
+			if v == exists {
+				s.tables = append(s.tables[:i], s.tables[i+1:]...)
+				break
+			}
+		}
+		delete(s.tableDdl, exists)
+	}
+	s.tables = append(s.tables, table)
+	s.tableMap[name] = table
+	s.tableDdl[table] = append(s.tableDdl[table], stmt.raw)
+	return nil
+}
+
+// partitionKey Read the partition key after PARTITION BY, like: RANGE (created_at) | LINEAR HASH (id)
+func (s *HelperDdl) partitionKey(c *ddlCursor, src string) string {
+	start := c.index
+	for !c.eof() && !c.is("(") {
+		c.next()
+	}
+	c.skip()
+	return ddlText(src, c.tokens[start:c.index])
+}
+
+// partitions Read the partition definitions of mysql: [PARTITIONS n] [SUBPARTITION BY ...] (PARTITION name VALUES ..., ...)
+func (s *HelperDdl) partitions(c *ddlCursor, src string) []*SchemaPartition {
+	for !c.eof() && !c.is("(") && !c.is("*/") {
+		c.skip()
+	}
+	result := make([]*SchemaPartition, 0)
+	for _, item := range c.group() {
+		tmp := &ddlCursor{tokens: item, lower: c.lower}
+		if !tmp.accept("PARTITION") {
+			continue
+		}
+		partition := &SchemaPartition{PartitionName: tmp.ident()}
+		if start := tmp.index; tmp.accept("VALUES") {
+			// VALUES LESS THAN (value) | VALUES LESS THAN MAXVALUE | VALUES IN (value, ...)
+			for !tmp.eof() {
+				if tmp.is("(") {
+					tmp.skip()
+					break
+				}
+				if tmp.accept("MAXVALUE") {
+					break
+				}
+				tmp.next()
+			}
+			partition.PartitionBound = ddlText(src, tmp.tokens[start:tmp.index])
+		}
+		result = append(result, partition)
+	}
+	return result
+}
+
+// partitionOf CREATE TABLE name PARTITION OF parent [( constraints )] { FOR VALUES ... | DEFAULT } [PARTITION BY ...], the partition is attached to the parent.
+func (s *HelperDdl) partitionOf(c *ddlCursor, stmt *ddlStatement, name string) error {
+	schema, parent := c.name()
+	table, partitions := s.table(schema, parent), (*[]*SchemaPartition)(nil)
+	switch {
+	case table != nil:
+		partitions = &table.Partitions
+	case s.schema(schema) && s.partitionMap[parent] != nil:
+		table, partitions = s.partitionTable[parent], &s.partitionMap[parent].Partitions
+	default:
+		return nil
+	}
+	if c.is("(") {
+		c.skip()
+	}
+	partition := &SchemaPartition{PartitionName: name}
+	start := c.index
+	for !c.eof() && !c.is("PARTITION", "BY") {
+		c.skip()
+	}
+	partition.PartitionBound = ddlText(stmt.src, c.tokens[start:c.index])
+	if c.accept("PARTITION", "BY") {
+		partition.PartitionKey = s.partitionKey(c, stmt.src)
+	}
+	*partitions = append(*partitions, partition)
+	s.partitionMap[name] = partition
+	s.partitionTable[name] = table
+	s.tableDdl[table] = append(s.tableDdl[table], stmt.raw)
+	return nil
+}
+
+// createView The columns of the view are inferred from the select list, the unrecognized expressions are text columns.
+func (s *HelperDdl) createView(c *ddlCursor, stmt *ddlStatement, tableType string) error {
+	c.accept("IF", "NOT", "EXISTS")
+	schema, name := c.name()
+	if name == "" {
+		return fmt.Errorf("view name is missing: %s", stmt.raw)
+	}
+	if !s.schema(schema) {
+		return nil
+	}
+	var names []string
+	if c.is("(") {
+		names = c.columns()
+	}
+	for !c.eof() && !c.accept("AS") {
+		c.skip() // WITH ( view_option_name = ... )
+	}
+	tableSchema, tableComment := s.app.cfg.TableSchemaName, ""
+	view := &SchemaTable{
+		app:          s.app,
+		TableSchema:  &tableSchema,
+		TableName:    &name,
+		TableType:    &tableType,
+		TableComment: &tableComment,
+	}
+	for _, v := range s.selectColumns(c) {
+		if len(names) > len(view.Column) {
+			v.name = names[len(view.Column)]
+		}
+		position := len(view.Column) + 1
+		column := &SchemaColumn{}
+		if v.column != nil {
+			*column = *v.column
+		} else {
+			nullable, dataType := "YES", "text"
+			column.IsNullable = &nullable
+			column.DataType = &dataType
+			column.ColumnType = &dataType
+			if v.cast != "" {
+				s.columnType(column, v.cast, v.cast, "", false)
+			}
+		}
+		comment, key, extra := "", "", ""
+		if column.ColumnComment != nil {
+			comment = *column.ColumnComment
+		}
+		columnName := v.name
+		column.table = view
+		column.TableSchema = view.TableSchema
+		column.TableName = view.TableName
+		column.ColumnName = &columnName
+		column.OrdinalPosition = &position
+		column.ColumnComment = &comment
+		column.ColumnKey = &key
+		column.Extra = &extra
+		view.Column = append(view.Column, column)
+	}
+	if exists, ok := s.tableMap[name]; ok {
+		for i, v := range s.tables {
+			if v == exists {
+				s.tables = append(s.tables[:i], s.tables[i+1:]...)
+				break
+			}
+		}
+		delete(s.tableDdl, exists)
+	}
+	s.tables = append(s.tables, view)
+	s.tableMap[name] = view
+	s.tableDdl[view] = append(s.tableDdl[view], stmt.raw)
+	return nil
+}
+
+// ddlSelectColumn A column of the select list.
+type ddlSelectColumn struct {
+	name   string
+	column *SchemaColumn // the column is selected directly from a known table
+	cast   string        // postgresql: expression::type
+}
+
+// selectColumns Read the select list and the from clause of the first SELECT.
+func (s *HelperDdl) selectColumns(c *ddlCursor) []*ddlSelectColumn {
+	for !c.eof() && !c.is("SELECT") {
+		c.skip() // WITH ... AS ( ... )
+	}
+	if !c.accept("SELECT") {
+		return nil
+	}
+	if c.accept("DISTINCT") && c.accept("ON") {
+		c.skip()
+	}
+	c.accept("ALL")
+	items := s.split(c, "FROM")
+	tables := make(map[string]*SchemaTable)
+	order := make([]*SchemaTable, 0, 2)
+	if c.accept("FROM") {
+		for _, item := range s.split(c, "WHERE", "GROUP", "HAVING", "WINDOW", "ORDER", "LIMIT", "UNION", "INTERSECT", "EXCEPT", "WITH") {
+			// table [AS] alias [JOIN table [AS] alias ON ...]
+			tmp := &ddlCursor{tokens: item, lower: c.lower}
+			for !tmp.eof() {
+				if tmp.is("(") {
+					tmp.skip() // sub query
+					tmp.accept("AS")
+					if t := tmp.peek(); t != nil && (t.kind == ddlTokenIdent || (t.kind == ddlTokenWord && !ddlSelectKeyword[strings.ToUpper(t.value)])) {
+						tables[tmp.ident()] = nil
+					}
+				} else if t := tmp.peek(); t.kind == ddlTokenWord || t.kind == ddlTokenIdent {
+					if t.kind == ddlTokenWord && ddlSelectKeyword[strings.ToUpper(t.value)] {
+						tmp.next()
+						continue
+					}
+					schema, name := tmp.name()
+					table := s.table(schema, name)
+					tables[name] = table
+					if table != nil {
+						order = append(order, table)
+					}
+					tmp.accept("AS")
+					if t = tmp.peek(); t != nil && (t.kind == ddlTokenIdent || (t.kind == ddlTokenWord && !ddlSelectKeyword[strings.ToUpper(t.value)])) {
+						tables[tmp.ident()] = table
+					}
+				}
+				// skip to the next joined table
+				for !tmp.eof() && !tmp.is("JOIN") {
+					tmp.skip()
+				}
+				tmp.accept("JOIN")
+			}
+		}
+	}
+	result := make([]*ddlSelectColumn, 0, len(items))
+	for _, item := range items {
+		n := len(item)
+		if n == 0 {
+			continue
+		}
+		// * | table.*
+		if item[n-1].kind == ddlTokenSymbol && item[n-1].value == "*" {
+			expand := order
+			if n == 3 {
+				tmp := &ddlCursor{tokens: item[:1], lower: c.lower}
+				expand = []*SchemaTable{tables[tmp.ident()]}
+			}
+			for _, table := range expand {
+				if table == nil {
+					continue
+				}
+				for _, v := range table.Column {
+					result = append(result, &ddlSelectColumn{name: *v.ColumnName, column: v})
+				}
+			}
+			continue
+		}
+		tmp := &ddlSelectColumn{}
+		expression := item
+		// expression [AS] alias
+		if n > 2 && item[n-2].kind == ddlTokenWord && strings.EqualFold(item[n-2].value, "AS") {
+			expression = item[:n-2]
+		} else if n > 1 && (item[n-1].kind == ddlTokenWord || item[n-1].kind == ddlTokenIdent) && item[n-2].kind != ddlTokenSymbol {
+			expression = item[:n-1]
+		} else if n > 1 && (item[n-1].kind == ddlTokenWord || item[n-1].kind == ddlTokenIdent) && item[n-2].value == ")" {
+			expression = item[:n-1]
+		}
+		if len(expression) < n {
+			alias := &ddlCursor{tokens: item[n-1:], lower: c.lower}
+			tmp.name = alias.ident()
+		}
+		// column | table.column | expression::type
+		if m := len(expression); m > 2 && expression[m-2].kind == ddlTokenSymbol && expression[m-2].value == "::" {
+			tmp.cast = strings.ToLower(expression[m-1].value)
+		} else if m > 1 && strings.EqualFold(expression[0].value, "COUNT") && expression[1].value == "(" {
+			tmp.cast = "bigint"
+		}
+		cursor := &ddlCursor{tokens: expression, lower: c.lower}
+		if t := cursor.peek(); len(expression) == 1 || len(expression) == 3 || tmp.cast != "" {
+			if t.kind == ddlTokenWord || t.kind == ddlTokenIdent {
+				prefix, name := cursor.name()
+				if cursor.eof() || cursor.is("::") {
+					if tmp.name == "" {
+						tmp.name = name
+					}
+					if tmp.cast == "" {
+						tmp.column = s.selectColumn(tables, order, prefix, name)
+					}
+				}
+			}
+		}
+		if tmp.name == "" {
+			continue // the name of the expression depends on the database
+		}
+		result = append(result, tmp)
+	}
+	return result
+}
+
+// selectColumn Find the column in the tables of the from clause.
+func (s *HelperDdl) selectColumn(tables map[string]*SchemaTable, order []*SchemaTable, prefix string, name string) *SchemaColumn {
+	candidates := order
+	if prefix != "" {
+		candidates = []*SchemaTable{tables[prefix]}
+	}
+	for _, table := range candidates {
+		if table == nil {
+			continue
+		}
+		for _, v := range table.Column {
+			if *v.ColumnName == name {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// ddlSelectKeyword The words which are not an alias in the from clause.
+var ddlSelectKeyword = map[string]bool{
+	"AS":      true,
+	"ON":      true,
+	"USING":   true,
+	"JOIN":    true,
+	"INNER":   true,
+	"LEFT":    true,
+	"RIGHT":   true,
+	"FULL":    true,
+	"OUTER":   true,
+	"CROSS":   true,
+	"NATURAL": true,
+	"LATERAL": true,
+	"ONLY":    true,
+}
+
+// split Read the tokens until one of the words at the top level, and split them by top-level commas.
+func (s *HelperDdl) split(c *ddlCursor, words ...string) [][]*ddlToken {
+	result := make([][]*ddlToken, 0, 8)
+	start := c.index
+	for !c.eof() {
+		stop := false
+		for _, w := range words {
+			if c.is(w) {
+				stop = true
+				break
+			}
+		}
+		if stop {
+			break
+		}
+		if c.is(",") {
+			result = append(result, c.tokens[start:c.index])
+			c.next()
+			start = c.index
+			continue
+		}
+		c.skip()
+	}
+	if c.index > start {
+		result = append(result, c.tokens[start:c.index])
+	}
+	return result
+}
+
+// tableConstraint Table level constraint or index, returns false if it is a column definition.
+func (s *HelperDdl) tableConstraint(c *ddlCursor, table *SchemaTable, src string) bool {
+	t := c.peek()
+	if t == nil || t.kind != ddlTokenWord {
+		return false
+	}
+	name := ""
+	if c.accept("CONSTRAINT") {
+		name = c.ident()
+	}
+	switch {
+	case c.accept("PRIMARY", "KEY"):
+		columns := c.columns()
+		s.columnKey(table, "PRI", columns...)
+		s.index(table, name, "", true, true, columns)
+	case c.accept("UNIQUE"):
+		_ = c.accept("KEY") || c.accept("INDEX")
+		if !c.is("(") {
+			name = c.ident()
+		}
+		columns, expression, indexed := c.keyColumns()
+		s.columnKey(table, "UNI", columns...)
+		if !expression {
+			s.uniqueIndex(table, name, columns)
+		}
+		s.index(table, name, "", true, false, indexed)
+	case c.accept("FOREIGN", "KEY"):
+		if !c.is("(") {
+			c.ident()
+		}
+		columns := c.columns()
+		s.columnKey(table, "MUL", columns...)
+		if c.accept("REFERENCES") {
+			s.foreignKey(c, table, name, columns)
+		}
+	case c.indexClause():
+		method := strings.ToUpper(c.next().value)
+		if method == "KEY" || method == "INDEX" {
+			method = ""
+		}
+		_ = c.accept("KEY") || c.accept("INDEX")
+		if !c.is("(") && !c.is("USING") {
+			name = c.ident()
+		}
+		if c.accept("USING") {
+			method = strings.ToUpper(c.ident())
+		}
+		columns := c.columns()
+		if c.accept("USING") {
+			method = strings.ToUpper(c.ident())
+		}
+		s.columnKey(table, "MUL", columns...)
+		s.index(table, name, method, false, false, columns)
+	case c.accept("CHECK"):
+		s.check(c, table, name, "", src)
+	case c.is("EXCLUDE"), c.is("LIKE"):
+	default:
+		return false
+	}
+	return true
+}
+
+// check Add a check constraint of the table, the name is generated like the database if it is empty.
+func (s *HelperDdl) check(c *ddlCursor, table *SchemaTable, name string, column string, src string) {
+	start := c.index
+	c.skip()
+	if c.index == start {
+		return
+	}
+	if name == "" {
+		switch {
+		case s.mysql():
+			name = fmt.Sprintf("%s_chk_%d", *table.TableName, len(table.TableCheck)+1)
+		case column != "":
+			name = fmt.Sprintf("%s_%s_check", *table.TableName, column)
+		default:
+			name = fmt.Sprintf("%s_check", *table.TableName)
+		}
+	}
+	table.TableCheck = append(table.TableCheck, &SchemaCheck{
+		ConstraintName: name,
+		CheckClause:    checkClause(ddlText(src, c.tokens[start:c.index]), s.mysql()),
+	})
+}
+
+// index Add an index of the table, the name is generated like the database if it is empty, the expressions are omitted from the columns.
+func (s *HelperDdl) index(table *SchemaTable, name string, method string, unique bool, primary bool, columns []string) {
+	switch {
+	case primary && s.mysql():
+		name = "PRIMARY"
+	case name != "":
+	case primary:
+		name = fmt.Sprintf("%s_pkey", *table.TableName)
+	case s.mysql() && len(columns) > 0:
+		name = columns[0]
+	case unique:
+		name = fmt.Sprintf("%s_%s_key", *table.TableName, strings.Join(columns, "_"))
+	default:
+		name = fmt.Sprintf("%s_%s_idx", *table.TableName, strings.Join(columns, "_"))
+	}
+	if method == "" {
+		switch {
+		case s.postgres():
+			method = "btree"
+		case s.mysql():
+			method = "BTREE"
+		}
+	}
+	table.TableIndex = append(table.TableIndex, &SchemaIndex{
+		IndexName: name,
+		Unique:    unique || primary,
+		Columns:   columns,
+		Primary:   primary,
+		Method:    method,
+	})
+}
+
+// uniqueIndex Add a unique index of the table, the name is generated like postgresql if it is empty.
+func (s *HelperDdl) uniqueIndex(table *SchemaTable, name string, columns []string) {
+	if len(columns) == 0 {
+		return
+	}
+	if name == "" {
+		name = fmt.Sprintf("%s_%s_key", *table.TableName, strings.Join(columns, "_"))
+	}
+	table.TableUniqueIndex = append(table.TableUniqueIndex, &SchemaIndex{
+		IndexName: name,
+		Unique:    true,
+		Columns:   columns,
+	})
+}
+
+// foreignKey Add a foreign key of the table, read the referenced table and columns after REFERENCES.
+// The referenced columns are empty if it refers to the primary key of the referenced table.
+func (s *HelperDdl) foreignKey(c *ddlCursor, table *SchemaTable, name string, columns []string) {
+	schema, referenced := c.name()
+	var referencedColumns []string
+	if c.is("(") {
+		referencedColumns = c.columns()
+	}
+	if len(columns) == 0 || referenced == "" || (len(referencedColumns) > 0 && len(referencedColumns) != len(columns)) {
+		return
+	}
+	if name == "" {
+		name = fmt.Sprintf("%s_%s_fkey", *table.TableName, strings.Join(columns, "_"))
+	}
+	switch {
+	case s.schema(schema):
+		schema = *table.TableSchema
+	case schema == "":
+		schema = s.search
+	}
+	table.TableForeignKey = append(table.TableForeignKey, &SchemaForeignKey{
+		ConstraintName:    name,
+		Columns:           columns,
+		ReferencedSchema:  schema,
+		ReferencedTable:   referenced,
+		ReferencedColumns: referencedColumns,
+	})
+}
+
+// columnKey Mark the column key like mysql, a single column unique key is 'UNI', the first column of other keys is 'MUL'.
+func (s *HelperDdl) columnKey(table *SchemaTable, key string, columns ...string) {
+	if len(columns) == 0 {
+		return
+	}
+	if key == "UNI" && len(columns) > 1 {
+		key = "MUL"
+	}
+	for i, name := range columns {
+		if key != "PRI" && i > 0 {
+			break
+		}
+		for _, v := range table.Column {
+			if *v.ColumnName != name {
+				continue
+			}
+			if key == "PRI" {
+				no := "NO"
+				v.IsNullable = &no
+			}
+			current := ""
+			if v.ColumnKey != nil {
+				current = *v.ColumnKey
+			}
+			if current == "PRI" || current == "UNI" && key == "MUL" || current == key {
+				break
+			}
+			if key == "PRI" {
+				table.TablePrimaryKey = append(table.TablePrimaryKey, name)
+			}
+			tmp := key
+			v.ColumnKey = &tmp
+			break
+		}
+	}
+}
+
+// columnConstraint The keywords that end the data type of column definition.
+var ddlColumnConstraint = map[string]*struct{}{
+	"NOT":            {},
+	"NULL":           {},
+	"DEFAULT":        {},
+	"PRIMARY":        {},
+	"UNIQUE":         {},
+	"KEY":            {},
+	"AUTO_INCREMENT": {},
+	"AUTOINCREMENT":  {},
+	"COMMENT":        {},
+	"REFERENCES":     {},
+	"CHECK":          {},
+	"CONSTRAINT":     {},
+	"COLLATE":        {},
+	"CHARSET":        {},
+	"GENERATED":      {},
+	"AS":             {},
+	"ON":             {},
+	"STORED":         {},
+	"VIRTUAL":        {},
+	"VISIBLE":        {},
+	"INVISIBLE":      {},
+	"SRID":           {},
+	"COLUMN_FORMAT":  {},
+	"STORAGE":        {},
+}
+
+func (s *HelperDdl) column(c *ddlCursor, table *SchemaTable, src string) *SchemaColumn {
+	t := c.peek()
+	if t == nil || (t.kind != ddlTokenWord && t.kind != ddlTokenIdent) {
+		return nil
+	}
+	name := c.ident()
+	position := len(table.Column) + 1
+	nullable, comment, key, extra := "YES", "", "", ""
+	column := &SchemaColumn{
+		table:           table,
+		TableSchema:     table.TableSchema,
+		TableName:       table.TableName,
+		ColumnName:      &name,
+		OrdinalPosition: &position,
+		IsNullable:      &nullable,
+		ColumnComment:   &comment,
+		ColumnKey:       &key,
+		Extra:           &extra,
+	}
+	// data type, such as: int unsigned | character varying(32) | timestamp(6) without time zone | integer[]
+	words, columnType, args, isArray := make([]string, 0, 4), "", "", false
+	for !c.eof() {
+		t = c.peek()
+		if t.kind == ddlTokenWord {
+			upper := strings.ToUpper(t.value)
+			if _, ok := ddlColumnConstraint[upper]; ok {
+				break
+			}
+			if upper == "CHARACTER" && c.is("CHARACTER", "SET") {
+				break
+			}
+			c.next()
+			lower := strings.ToLower(t.value)
+			columnType = strings.TrimSpace(fmt.Sprintf("%s %s", columnType, lower))
+			switch upper {
+			case "UNSIGNED", "SIGNED", "ZEROFILL":
+			default:
+				words = append(words, lower)
+			}
+			continue
+		}
+		if t.kind == ddlTokenSymbol && t.value == "(" {
+			start := t.start
+			c.group()
+			args = strings.TrimSpace(src[start+1 : c.tokens[c.index-1].start])
+			columnType = fmt.Sprintf("%s(%s)", columnType, args)
+			continue
+		}
+		if t.kind == ddlTokenSymbol && (t.value == "[" || t.value == "]") {
+			c.next()
+			if t.value == "]" {
+				isArray = true
+				columnType += "[]"
+			}
+			continue
+		}
+		if t.kind == ddlTokenSymbol && t.value == "." && len(words) > 0 {
+			// schema.type, the schema is ignored
+			c.next()
+			if n := c.next(); n != nil {
+				words[len(words)-1] = strings.ToLower(n.value)
+				columnType = strings.ToLower(n.value)
+			}
+			continue
+		}
+		if t.kind == ddlTokenSymbol && t.value == "-" && len(words) > 0 {
+			// USER-DEFINED
+			c.next()
+			if n := c.next(); n != nil {
+				words[len(words)-1] = fmt.Sprintf("%s-%s", words[len(words)-1], strings.ToLower(n.value))
+				columnType = fmt.Sprintf("%s-%s", columnType, strings.ToLower(n.value))
+			}
+			continue
+		}
+		break
+	}
+	s.columnType(column, strings.Join(words, " "), columnType, args, isArray)
+	// column constraints
+	constraint := ""
+	for !c.eof() {
+		switch {
+		case c.accept("CONSTRAINT"):
+			constraint = c.ident()
+			continue
+		case c.accept("CHECK"):
+			s.check(c, table, constraint, name, src)
+		case c.accept("NOT", "NULL"):
+			*column.IsNullable = "NO"
+		case c.accept("NULL"):
+			*column.IsNullable = "YES"
+		case c.accept("DEFAULT"):
+			start := c.index
+			for !c.eof() {
+				t = c.peek()
+				if t.kind == ddlTokenWord {
+					if _, ok := ddlColumnConstraint[strings.ToUpper(t.value)]; ok {
+						break
+					}
+				}
+				c.skip()
+			}
+			if c.index > start {
+				value := s.columnDefault(src, c.tokens[start:c.index])
+				column.ColumnDefault = &value
+			}
+		case c.accept("PRIMARY", "KEY"):
+			*column.ColumnKey = "PRI"
+			*column.IsNullable = "NO"
+			table.TablePrimaryKey = append(table.TablePrimaryKey, name)
+			s.index(table, constraint, "", true, true, []string{name})
+		case c.accept("UNIQUE"):
+			c.accept("KEY")
+			if *column.ColumnKey != "PRI" {
+				*column.ColumnKey = "UNI"
+			}
+			s.uniqueIndex(table, constraint, []string{name})
+			s.index(table, constraint, "", true, false, []string{name})
+		case c.accept("KEY"):
+			if *column.ColumnKey == "" {
+				*column.ColumnKey = "MUL"
+			}
+		case c.accept("AUTO_INCREMENT"), c.accept("AUTOINCREMENT"):
+			*column.Extra = "auto_increment"
+			if table.TableFieldSerial == "" {
+				table.TableFieldSerial = name
+			}
+		case c.accept("COMMENT"):
+			if t = c.next(); t != nil {
+				*column.ColumnComment = t.value
+			}
+		case c.accept("REFERENCES"):
+			s.foreignKey(c, table, "", []string{name})
+			if *column.ColumnKey == "" {
+				*column.ColumnKey = "MUL"
+			}
+		case c.accept("COLLATE"):
+			collation := c.ident()
+			column.CollationName = &collation
+		case c.accept("CHARACTER", "SET"), c.accept("CHARSET"):
+			charset := c.ident()
+			column.CharacterSetName = &charset
+		case c.accept("ON", "UPDATE"):
+			*column.Extra = strings.TrimSpace(fmt.Sprintf("%s on update %s", *column.Extra, strings.ToUpper(c.ident())))
+			if c.is("(") {
+				c.skip()
+			}
+		case c.accept("GENERATED", "ALWAYS", "AS", "IDENTITY"), c.accept("GENERATED", "BY", "DEFAULT", "AS", "IDENTITY"):
+			*column.IsNullable = "NO"
+			identity, generation := "YES", "BY DEFAULT"
+			if strings.EqualFold(c.tokens[c.index-3].value, "ALWAYS") {
+				generation = "ALWAYS"
+			}
+			column.IsIdentity, column.IdentityGeneration = &identity, &generation
+			if table.TableFieldSerial == "" {
+				table.TableFieldSerial = name
+			}
+		case c.accept("GENERATED", "ALWAYS", "AS"), c.accept("AS"):
+			c.skip()
+			if c.accept("STORED") {
+				*column.Extra = "STORED GENERATED"
+			} else {
+				c.accept("VIRTUAL")
+				*column.Extra = "VIRTUAL GENERATED"
+			}
+		default:
+			c.skip()
+		}
+		constraint = ""
+	}
+	// postgresql serial
+	if column.DataType != nil && s.postgres() {
+		if serial, ok := map[string]string{"smallserial": "smallint", "serial": "integer", "bigserial": "bigint"}[*column.DataType]; ok {
+			*column.DataType = serial
+			*column.IsNullable = "NO"
+			value := fmt.Sprintf("nextval('%s_%s_seq'::regclass)", *table.TableName, name)
+			column.ColumnDefault = &value
+			if table.TableFieldSerial == "" {
+				table.TableFieldSerial = name
+			}
+		}
+	}
+	if column.ColumnDefault != nil && s.postgres() && ddlNextval.MatchString(*column.ColumnDefault) && table.TableFieldSerial == "" {
+		table.TableFieldSerial = name
+	}
+	return column
+}
+
+// ddlUdtName The internal type name of postgresql like pg_type.typname.
+func ddlUdtName(dataType string) string {
+	switch dataType {
+	case "smallint":
+		return "int2"
+	case "integer":
+		return "int4"
+	case "bigint":
+		return "int8"
+	case "character varying":
+		return "varchar"
+	case "character":
+		return "bpchar"
+	case "boolean":
+		return "bool"
+	case "double precision":
+		return "float8"
+	case "real":
+		return "float4"
+	case "timestamp without time zone":
+		return "timestamp"
+	case "timestamp with time zone":
+		return "timestamptz"
+	case "time without time zone":
+		return "time"
+	case "time with time zone":
+		return "timetz"
+	}
+	return dataType
+}
+
+// columnDefault Default value text, the single string literal of mysql is unquoted like information_schema.
+func (s *HelperDdl) columnDefault(src string, tokens []*ddlToken) string {
+	if len(tokens) == 1 && tokens[0].kind == ddlTokenString && s.mysql() {
+		return tokens[0].value
+	}
+	return src[tokens[0].start:tokens[len(tokens)-1].end]
+}
+
+// columnType Set data type like information_schema of the dialect.
+func (s *HelperDdl) columnType(column *SchemaColumn, dataType string, columnType string, args string, isArray bool) {
+	column.ColumnType = &columnType
+	numbers := make([]int, 0, 2)
+	for _, v := range strings.Split(args, ",") {
+		if number, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			numbers = append(numbers, number)
+		}
+	}
+	switch {
+	case s.mysql():
+		switch dataType {
+		case "integer":
+			dataType = "int"
+		case "bool", "boolean":
+			dataType = "tinyint"
+			if args == "" {
+				*column.ColumnType = "tinyint(1)"
+			}
+		case "dec", "fixed", "numeric":
+			dataType = "decimal"
+		case "double precision", "real":
+			dataType = "double"
+		case "character varying", "varchar2":
+			dataType = "varchar"
+		case "character":
+			dataType = "char"
+		}
+	case s.postgres():
+		switch dataType {
+		case "int", "int4":
+			dataType = "integer"
+		case "int2":
+			dataType = "smallint"
+		case "int8":
+			dataType = "bigint"
+		case "varchar":
+			dataType = "character varying"
+		case "char", "bpchar":
+			dataType = "character"
+		case "bool":
+			dataType = "boolean"
+		case "float8", "double":
+			dataType = "double precision"
+		case "float4", "float":
+			dataType = "real"
+		case "decimal":
+			dataType = "numeric"
+		case "timestamp":
+			dataType = "timestamp without time zone"
+		case "timestamptz":
+			dataType = "timestamp with time zone"
+		case "time":
+			dataType = "time without time zone"
+		case "timetz":
+			dataType = "time with time zone"
+		case "user-defined":
+			dataType = "USER-DEFINED"
+		case "array":
+			dataType = "ARRAY"
+		}
+		if isArray {
+			udtName := "_" + ddlUdtName(dataType)
+			column.UdtName = &udtName
+			dataType = "ARRAY"
+		}
+	}
+	column.DataType = &dataType
+	if isArray || len(numbers) == 0 {
+		return
+	}
+	if strings.Contains(dataType, "char") || strings.Contains(dataType, "text") || strings.Contains(dataType, "binary") {
+		column.CharacterMaximumLength = &numbers[0]
+		column.CharacterOctetLength = &numbers[0]
+		return
+	}
+	column.NumericPrecision = &numbers[0]
+	if len(numbers) > 1 {
+		column.NumericScale = &numbers[1]
+	}
+}
+
+func (s *HelperDdl) createIndex(c *ddlCursor, stmt *ddlStatement) error {
+	key := "MUL"
+	if c.accept("UNIQUE") {
+		key = "UNI"
+	}
+	c.accept("INDEX")
+	c.accept("CONCURRENTLY")
+	c.accept("IF", "NOT", "EXISTS")
+	index := ""
+	if !c.is("ON") {
+		_, index = c.name()
+	}
+	if !c.accept("ON") {
+		return nil
+	}
+	c.accept("ONLY")
+	schema, name := c.name()
+	table := s.table(schema, name)
+	if table == nil {
+		return nil
+	}
+	method := ""
+	if c.accept("USING") {
+		method = c.ident()
+		if s.mysql() {
+			method = strings.ToUpper(method)
+		}
+	}
+	columns, expression, indexed := c.keyColumns()
+	s.columnKey(table, key, columns...)
+	s.index(table, index, method, key == "UNI", false, indexed)
+	if key == "UNI" && !expression {
+		partial := false
+		for !c.eof() && !partial {
+			partial = c.accept("WHERE")
+			c.skip()
+		}
+		if !partial {
+			s.uniqueIndex(table, index, columns)
+		}
+	}
+	s.tableDdl[table] = append(s.tableDdl[table], stmt.raw)
+	return nil
+}
+
+func (s *HelperDdl) alterTable(c *ddlCursor, stmt *ddlStatement) error {
+	c.accept("IF", "EXISTS")
+	c.accept("ONLY")
+	schema, name := c.name()
+	table := s.table(schema, name)
+	if table == nil {
+		return nil
+	}
+	switch {
+	case c.accept("ADD"):
+		if c.accept("COLUMN") || !s.tableConstraint(c, table, stmt.src) {
+			c.accept("IF", "NOT", "EXISTS")
+			if column := s.column(c, table, stmt.src); column != nil {
+				table.Column = append(table.Column, column)
+			}
+		}
+	case c.accept("ALTER"):
+		c.accept("COLUMN")
+		columnName := c.ident()
+		for _, v := range table.Column {
+			if *v.ColumnName != columnName {
+				continue
+			}
+			switch {
+			case c.accept("SET", "DEFAULT"):
+				if !c.eof() {
+					value := s.columnDefault(stmt.src, c.tokens[c.index:])
+					v.ColumnDefault = &value
+					if s.postgres() && ddlNextval.MatchString(value) && table.TableFieldSerial == "" {
+						table.TableFieldSerial = columnName
+					}
+				}
+			case c.accept("SET", "NOT", "NULL"):
+				*v.IsNullable = "NO"
+			case c.accept("ADD", "GENERATED"):
+				identity, generation := "YES", "BY DEFAULT"
+				if c.accept("ALWAYS") {
+					generation = "ALWAYS"
+				}
+				v.IsIdentity, v.IdentityGeneration = &identity, &generation
+				if table.TableFieldSerial == "" {
+					table.TableFieldSerial = columnName
+				}
+			}
+			break
+		}
+	}
+	s.tableDdl[table] = append(s.tableDdl[table], stmt.raw)
+	return nil
+}
+
+func (s *HelperDdl) commentOn(c *ddlCursor, stmt *ddlStatement) error {
+	switch {
+	case c.accept("TABLE"), c.accept("VIEW"), c.accept("MATERIALIZED", "VIEW"):
+		schema, name := c.name()
+		table := s.table(schema, name)
+		if table == nil || !c.accept("IS") {
+			return nil
+		}
+		if t := c.next(); t != nil && t.kind == ddlTokenString {
+			*table.TableComment = t.value
+		}
+		s.tableDdl[table] = append(s.tableDdl[table], stmt.raw)
+	case c.accept("COLUMN"):
+		names := []string{c.ident()}
+		for c.accept(".") {
+			names = append(names, c.ident())
+		}
+		if len(names) < 2 {
+			return nil
+		}
+		schema, name, columnName := "", names[len(names)-2], names[len(names)-1]
+		if len(names) > 2 {
+			schema = names[len(names)-3]
+		}
+		table := s.table(schema, name)
+		if table == nil || !c.accept("IS") {
+			return nil
+		}
+		if t := c.next(); t != nil && t.kind == ddlTokenString {
+			for _, v := range table.Column {
+				if *v.ColumnName == columnName {
+					comment := t.value
+					v.ColumnComment = &comment
+					break
+				}
+			}
+		}
+		s.tableDdl[table] = append(s.tableDdl[table], stmt.raw)
+	}
+	return nil
+}
+
+func (s *HelperDdl) GetAllTable() []*SchemaTable {
+	return s.tables
+}
+
+func (s *HelperDdl) QueryTableDefineSql(ctx context.Context, table *SchemaTable) error {
+	statements := make([]string, 0, 8)
+	for _, c := range table.Column {
+		if !s.postgres() || c.enum == nil || table.isView() {
+			continue
+		}
+		// only enums created by CREATE TYPE, mysql enum() and set() are a part of the column definition
+		if enum, ok := s.enums[c.enum.EnumName]; !ok || enum != c.enum {
+			continue
+		}
+		if _, ok := s.enumDdl[c.enum.EnumName]; ok {
+			continue
+		}
+		s.enumDdl[c.enum.EnumName] = struct{}{}
+		values := make([]string, 0, len(c.enum.Values))
+		for _, v := range c.enum.Values {
+			values = append(values, fmt.Sprintf("'%s'", strings.ReplaceAll(v, "'", "''")))
+		}
+		// CREATE TYPE does not support IF NOT EXISTS
+		statements = append(statements, fmt.Sprintf("DO $$ BEGIN CREATE TYPE %s AS ENUM (%s); EXCEPTION WHEN duplicate_object THEN NULL; END $$", c.enum.EnumName, strings.Join(values, ", ")))
+	}
+	for _, c := range table.Column {
+		if c.ColumnDefault == nil || table.isView() {
+			continue
+		}
+		for _, result := range ddlNextval.FindAllStringSubmatch(*c.ColumnDefault, -1) {
+			name := result[1]
+			if !strings.Contains(name, `"`) {
+				name = strings.ToLower(name)
+			}
+			name = strings.ReplaceAll(name, `"`, "")
+			sequence, ok := s.sequences[name]
+			if index := strings.LastIndex(name, "."); !ok && index >= 0 {
+				sequence, ok = s.sequences[name[index+1:]]
+			}
+			if ok {
+				statements = append(statements, ddlCreateSequenceReplace.ReplaceAllString(sequence, "CREATE SEQUENCE IF NOT EXISTS "))
+			}
+		}
+	}
+	for _, v := range s.tableDdl[table] {
+		switch {
+		case table.isView() && createViewRegexpReplace.MatchString(v):
+			if *table.TableType == tableTypeMaterializedView {
+				v = createViewRegexpReplace.ReplaceAllString(v, "CREATE MATERIALIZED VIEW IF NOT EXISTS ")
+			} else {
+				v = createViewRegexpReplace.ReplaceAllString(v, "CREATE OR REPLACE VIEW ")
+			}
+		case ddlCreateTableReplace.MatchString(v):
+			v = ddlCreateTableReplace.ReplaceAllString(v, "CREATE TABLE IF NOT EXISTS ")
+			v = autoIncrementRegexpReplace.ReplaceAllString(v, "${1}=1")
+			v = partitionRegexpReplace.ReplaceAllString(v, "${1}")
+		case ddlCreateIndexReplace.MatchString(v):
+			v = ddlCreateIndexReplace.ReplaceAllString(v, "CREATE ${1}INDEX IF NOT EXISTS ")
+		}
+		statements = append(statements, v)
+	}
+	table.DDL = strings.Join(statements, ";\n")
+	return nil
+}
@@ -0,0 +1,249 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/cd365/hey/v2"
+)
+
+// ddlTables Parse the DDL by the helper of DDL files, the tables are keyed by name.
+func ddlTables(t *testing.T, driver string, src string) map[string]*SchemaTable {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "schema.sql")
+	if err := os.WriteFile(filename, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	app := NewApp(context.Background(), &Config{
+		Driver:      driver,
+		DdlFiles:    []string{filename},
+		Concurrency: 1,
+	})
+	if err := app.initial(); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := app.helper.QueryAllTable(ctx); err != nil {
+		t.Fatal(err)
+	}
+	result := make(map[string]*SchemaTable)
+	for _, table := range app.helper.GetAllTable() {
+		if err := app.helper.QueryTableDefineSql(ctx, table); err != nil {
+			t.Fatal(err)
+		}
+		result[*table.TableName] = table
+	}
+	return result
+}
+
+func TestHelperDdl(t *testing.T) {
+	type want struct {
+		table      string
+		columns    []string
+		primary    []string
+		indexes    []string
+		checks     []string
+		partitions []string
+		enums      []string // columns of enum type
+		contains   []string // the output DDL contains
+		excludes   []string // the output DDL does not contain
+	}
+	cases := []struct {
+		name   string
+		driver string
+		src    string
+		tables []string
+		want   []want
+	}{
+		{
+			name:   "mysql table",
+			driver: hey.DriverNameMysql,
+			src: "CREATE TABLE `account` (\n" +
+				"  `id` bigint unsigned NOT NULL AUTO_INCREMENT,\n" +
+				"  `email` varchar(64) NOT NULL COMMENT 'login email',\n" +
+				"  `status` enum('on','off') NOT NULL DEFAULT 'on',\n" +
+				"  `tags` set('a','b') DEFAULT NULL,\n" +
+				"  PRIMARY KEY (`id`),\n" +
+				"  UNIQUE KEY `email` (`email`),\n" +
+				"  KEY `idx_email_lower` ((lower(`email`)))\n" +
+				") ENGINE=InnoDB AUTO_INCREMENT=100 DEFAULT CHARSET=utf8mb4 COMMENT='accounts';\n",
+			tables: []string{"account"},
+			want: []want{
+				{
+					table:    "account",
+					columns:  []string{"id", "email", "status", "tags"},
+					primary:  []string{"id"},
+					indexes:  []string{"PRIMARY", "email", "idx_email_lower"},
+					enums:    []string{"status", "tags"},
+					contains: []string{"CREATE TABLE IF NOT EXISTS `account`", "AUTO_INCREMENT=1 "},
+					excludes: []string{"CREATE TYPE", "AUTO_INCREMENT=100"},
+				},
+			},
+		},
+		{
+			name:   "mysql partition and check",
+			driver: hey.DriverNameMysql,
+			src: "CREATE TABLE `sales` (\n" +
+				"  `id` int NOT NULL,\n" +
+				"  `year` int NOT NULL,\n" +
+				"  `amount` int NOT NULL,\n" +
+				"  PRIMARY KEY (`id`,`year`),\n" +
+				"  CONSTRAINT `sales_amount` CHECK ((`amount` > 0))\n" +
+				") ENGINE=InnoDB\n" +
+				"/*!50100 PARTITION BY RANGE (`year`)\n" +
+				"(PARTITION p2023 VALUES LESS THAN (2024) ENGINE = InnoDB,\n" +
+				" PARTITION p2024 VALUES LESS THAN (2025) ENGINE = InnoDB) */;\n",
+			tables: []string{"sales"},
+			want: []want{
+				{
+					table:      "sales",
+					columns:    []string{"id", "year", "amount"},
+					primary:    []string{"id", "year"},
+					indexes:    []string{"PRIMARY"},
+					checks:     []string{"`amount` > 0"},
+					partitions: []string{"p2023", "p2024"},
+					contains:   []string{"PARTITION BY RANGE"},
+					excludes:   []string{"/*!50100"},
+				},
+			},
+		},
+		{
+			name:   "postgres enum and index",
+			driver: hey.DriverNamePostgres,
+			src: "CREATE TABLE person (\n" +
+				"  id bigserial PRIMARY KEY,\n" +
+				"  name text NOT NULL,\n" +
+				"  feeling mood,\n" +
+				"  CONSTRAINT person_name_check CHECK (char_length(name) <= 32)\n" +
+				");\n" +
+				"CREATE TYPE mood AS ENUM ('happy', 'it''s ok');\n" +
+				"CREATE INDEX person_name ON person (name);\n" +
+				"CREATE INDEX person_name_lower ON person (lower(name));\n" +
+				"COMMENT ON TABLE person IS 'people';\n",
+			tables: []string{"person"},
+			want: []want{
+				{
+					table:    "person",
+					columns:  []string{"id", "name", "feeling"},
+					primary:  []string{"id"},
+					indexes:  []string{"person_pkey", "person_name", "person_name_lower"},
+					checks:   []string{"char_length(name) <= 32"},
+					enums:    []string{"feeling"},
+					contains: []string{"CREATE TYPE mood AS ENUM ('happy', 'it''s ok')", "CREATE INDEX IF NOT EXISTS person_name ON person (name)"},
+				},
+			},
+		},
+		{
+			name:   "postgres key and index columns",
+			driver: hey.DriverNamePostgres,
+			src: "CREATE TABLE uuid_keyed (key uuid PRIMARY KEY, index varchar(32) NOT NULL, val text);\n" +
+				"CREATE INDEX uuid_keyed_index ON uuid_keyed (index);\n",
+			tables: []string{"uuid_keyed"},
+			want: []want{
+				{
+					table:   "uuid_keyed",
+					columns: []string{"key", "index", "val"},
+					primary: []string{"key"},
+					indexes: []string{"uuid_keyed_pkey", "uuid_keyed_index"},
+				},
+			},
+		},
+		{
+			name:   "postgres partition",
+			driver: hey.DriverNamePostgres,
+			src: "CREATE TABLE measurement (id int NOT NULL, logdate date NOT NULL, PRIMARY KEY (id, logdate)) PARTITION BY RANGE (logdate);\n" +
+				"CREATE TABLE measurement_y2024 PARTITION OF measurement FOR VALUES FROM ('2024-01-01') TO ('2025-01-01');\n" +
+				"CREATE TABLE measurement_y2025 PARTITION OF measurement FOR VALUES FROM ('2025-01-01') TO ('2026-01-01');\n" +
+				"CREATE TABLE city (id int PRIMARY KEY, name varchar(32));\n",
+			tables: []string{"city", "measurement"},
+			want: []want{
+				{
+					table:      "measurement",
+					columns:    []string{"id", "logdate"},
+					primary:    []string{"id", "logdate"},
+					indexes:    []string{"measurement_pkey"},
+					partitions: []string{"measurement_y2024", "measurement_y2025"},
+					contains:   []string{"PARTITION BY RANGE (logdate)", "PARTITION OF measurement"},
+					excludes:   []string{"CREATE TYPE"},
+				},
+				{
+					table:   "city",
+					columns: []string{"id", "name"},
+					primary: []string{"id"},
+					indexes: []string{"city_pkey"},
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tables := ddlTables(t, c.driver, c.src)
+			names := make([]string, 0, len(tables))
+			for name := range tables {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, c.tables) {
+				t.Fatalf("tables: got %q, want %q", names, c.tables)
+			}
+			for _, w := range c.want {
+				table, ok := tables[w.table]
+				if !ok {
+					t.Fatalf("table %s does not exist", w.table)
+				}
+				columns, enums := make([]string, 0), make([]string, 0)
+				for _, v := range table.Column {
+					columns = append(columns, *v.ColumnName)
+					if v.enum != nil {
+						enums = append(enums, *v.ColumnName)
+					}
+				}
+				indexes := make([]string, 0)
+				for _, v := range table.TableIndex {
+					indexes = append(indexes, v.IndexName)
+				}
+				checks := make([]string, 0)
+				for _, v := range table.TableCheck {
+					checks = append(checks, v.CheckClause)
+				}
+				partitions := make([]string, 0)
+				for _, v := range table.Partitions {
+					partitions = append(partitions, v.PartitionName)
+				}
+				for _, v := range []struct {
+					name      string
+					got, want []string
+				}{
+					{"columns", columns, w.columns},
+					{"primary", table.TablePrimaryKey, w.primary},
+					{"indexes", indexes, w.indexes},
+					{"checks", checks, w.checks},
+					{"partitions", partitions, w.partitions},
+					{"enums", enums, w.enums},
+				} {
+					if len(v.got) == 0 && len(v.want) == 0 {
+						continue
+					}
+					if !reflect.DeepEqual(v.got, v.want) {
+						t.Errorf("%s %s: got %q, want %q", w.table, v.name, v.got, v.want)
+					}
+				}
+				for _, v := range w.contains {
+					if !strings.Contains(table.DDL, v) {
+						t.Errorf("%s DDL does not contain %q:\n%s", w.table, v, table.DDL)
+					}
+				}
+				for _, v := range w.excludes {
+					if strings.Contains(table.DDL, v) {
+						t.Errorf("%s DDL contains %q:\n%s", w.table, v, table.DDL)
+					}
+				}
+			}
+		})
+	}
+}
@@ -1,15 +1,17 @@
 package app
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"regexp"
 	"strings"
-	"sync"
 )
 
 var (
 	autoIncrementRegexpReplace = regexp.MustCompile(`(AUTO_INCREMENT|auto_increment)=\d+`)
+	createViewRegexpReplace    = regexp.MustCompile(`(?is)^CREATE\s+.*?\s*VIEW\s+(IF\s+NOT\s+EXISTS\s+)?`)
+	partitionRegexpReplace     = regexp.MustCompile(`(?s)/\*!\d+\s*(PARTITION BY .*?)\s*\*/`)
 )
 
 type HelperMysql struct {
@@ -21,54 +23,199 @@ func NewMysql(app *App) Helper {
 	return &HelperMysql{app: app}
 }
 
-func (s *HelperMysql) QueryAllTable() (err error) {
+// QueryAllTable Query all tables of the schema, the catalog is queried in bulk and grouped by table name, the number of queries does not grow with the number of tables.
+func (s *HelperMysql) QueryAllTable(ctx context.Context) (err error) {
 	schema := s.app.cfg.TableSchemaName
-	prepare := "SELECT TABLE_SCHEMA AS table_schema, TABLE_NAME AS table_name, TABLE_COMMENT AS table_comment FROM information_schema.TABLES WHERE TABLE_TYPE = 'BASE TABLE' AND TABLE_SCHEMA = ? ORDER BY TABLE_NAME ASC;"
-	if err = s.app.way.TakeAll(&s.tables, prepare, schema); err != nil {
+	prepare := "SELECT TABLE_SCHEMA AS table_schema, TABLE_NAME AS table_name, TABLE_COMMENT AS table_comment, TABLE_TYPE AS table_type FROM information_schema.TABLES WHERE TABLE_TYPE IN ('BASE TABLE', 'VIEW') AND TABLE_SCHEMA = ? ORDER BY TABLE_NAME ASC;"
+	if err = s.app.takeAll(ctx, &s.tables, prepare, schema); err != nil {
 		return
 	}
-	once := &sync.Once{}
-	wg := &sync.WaitGroup{}
+	tables := make(map[string]*SchemaTable, len(s.tables))
 	for _, table := range s.tables {
 		table.app = s.app
-		wg.Add(1)
-		go func(table *SchemaTable) {
-			defer wg.Done()
-			columns, qer := s.getAllColumns(schema, table)
-			if qer != nil {
-				once.Do(func() { err = qer })
-				return
-			}
-			table.Column = columns
-		}(table)
+		if table.isView() {
+			// the comment of view is always 'VIEW'
+			table.TableComment = new(string)
+		}
+		tables[*table.TableName] = table
+	}
+	if err = s.queryColumns(ctx, schema, tables); err != nil {
+		return
 	}
-	wg.Wait()
-	return
+	return s.app.parallel(
+		ctx,
+		func(ctx context.Context) error { return s.queryUniqueIndex(ctx, schema, tables) },
+		func(ctx context.Context) error { return s.queryForeignKey(ctx, schema, tables) },
+		func(ctx context.Context) error { return s.queryPartition(ctx, schema, tables) },
+		func(ctx context.Context) error { return s.queryCheck(ctx, schema, tables) },
+		func(ctx context.Context) error { return s.queryIndex(ctx, schema, tables) },
+	)
 }
 
-func (s *HelperMysql) getAllColumns(schema string, table *SchemaTable) (list []*SchemaColumn, err error) {
-	if schema == "" || table == nil || table.TableName == nil || *table.TableName == "" {
-		return
+// queryIndex Query all indexes of the tables, the primary key is included and the functional key parts are omitted.
+func (s *HelperMysql) queryIndex(ctx context.Context, schema string, tables map[string]*SchemaTable) error {
+	prepare := "SELECT TABLE_NAME AS table_name, INDEX_NAME AS index_name, NON_UNIQUE AS non_unique, INDEX_TYPE AS index_type, COLUMN_NAME AS column_name FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = ? ORDER BY TABLE_NAME ASC, INDEX_NAME ASC, SEQ_IN_INDEX ASC"
+	return s.app.query(ctx, func(rows *sql.Rows) error {
+		var index *SchemaIndex
+		name, indexName, nonUnique, indexType, column := "", "", 0, "", sql.NullString{}
+		for rows.Next() {
+			if err := rows.Scan(&name, &indexName, &nonUnique, &indexType, &column); err != nil {
+				return err
+			}
+			table, ok := tables[name]
+			if !ok {
+				continue
+			}
+			if index == nil || index.IndexName != indexName || len(table.TableIndex) == 0 || table.TableIndex[len(table.TableIndex)-1] != index {
+				index = &SchemaIndex{
+					IndexName: indexName,
+					Unique:    nonUnique == 0,
+					Primary:   indexName == "PRIMARY",
+					Method:    indexType,
+				}
+				table.TableIndex = append(table.TableIndex, index)
+			}
+			if column.Valid && column.String != "" {
+				index.Columns = append(index.Columns, column.String)
+			}
+		}
+		return nil
+	}, prepare, schema)
+}
+
+// queryCheck Query the check constraints of the tables, the check constraints are supported since mysql 8.0.16.
+func (s *HelperMysql) queryCheck(ctx context.Context, schema string, tables map[string]*SchemaTable) error {
+	exists := 0
+	prepare := "SELECT COUNT(*) FROM information_schema.TABLES WHERE TABLE_SCHEMA = 'information_schema' AND TABLE_NAME = 'CHECK_CONSTRAINTS'"
+	err := s.app.query(ctx, func(rows *sql.Rows) error {
+		for rows.Next() {
+			if err := rows.Scan(&exists); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, prepare)
+	if err != nil || exists == 0 {
+		return err
 	}
-	prepare := "SELECT TABLE_SCHEMA AS table_schema, TABLE_NAME AS table_name, COLUMN_NAME AS column_name, ORDINAL_POSITION AS ordinal_position, COLUMN_DEFAULT AS column_default, IS_NULLABLE AS is_nullable, DATA_TYPE AS data_type, CHARACTER_MAXIMUM_LENGTH AS character_maximum_length, CHARACTER_OCTET_LENGTH AS character_octet_length, NUMERIC_PRECISION AS numeric_precision, NUMERIC_SCALE AS numeric_scale, CHARACTER_SET_NAME AS character_set_name, COLLATION_NAME AS collation_name, COLUMN_COMMENT AS column_comment, COLUMN_TYPE AS column_type, COLUMN_KEY AS column_key, EXTRA AS extra FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ordinal_position ASC"
-	list = make([]*SchemaColumn, 0)
-	if err = s.app.way.TakeAll(&list, prepare, schema, *table.TableName); err != nil {
-		return
+	prepare = "SELECT t.TABLE_NAME AS table_name, c.CONSTRAINT_NAME AS constraint_name, c.CHECK_CLAUSE AS check_clause FROM information_schema.TABLE_CONSTRAINTS t JOIN information_schema.CHECK_CONSTRAINTS c ON c.CONSTRAINT_SCHEMA = t.CONSTRAINT_SCHEMA AND c.CONSTRAINT_NAME = t.CONSTRAINT_NAME WHERE t.TABLE_SCHEMA = ? AND t.CONSTRAINT_TYPE = 'CHECK' ORDER BY t.TABLE_NAME ASC, c.CONSTRAINT_NAME ASC"
+	return s.app.query(ctx, func(rows *sql.Rows) error {
+		name, constraint, clause := "", "", ""
+		for rows.Next() {
+			if err := rows.Scan(&name, &constraint, &clause); err != nil {
+				return err
+			}
+			if table, ok := tables[name]; ok {
+				table.TableCheck = append(table.TableCheck, &SchemaCheck{
+					ConstraintName: constraint,
+					CheckClause:    checkClause(clause, true),
+				})
+			}
+		}
+		return nil
+	}, prepare, schema)
+}
+
+// queryColumns Query the columns of all tables and views in the schema.
+func (s *HelperMysql) queryColumns(ctx context.Context, schema string, tables map[string]*SchemaTable) error {
+	prepare := "SELECT TABLE_SCHEMA AS table_schema, TABLE_NAME AS table_name, COLUMN_NAME AS column_name, ORDINAL_POSITION AS ordinal_position, COLUMN_DEFAULT AS column_default, IS_NULLABLE AS is_nullable, DATA_TYPE AS data_type, CHARACTER_MAXIMUM_LENGTH AS character_maximum_length, CHARACTER_OCTET_LENGTH AS character_octet_length, NUMERIC_PRECISION AS numeric_precision, NUMERIC_SCALE AS numeric_scale, CHARACTER_SET_NAME AS character_set_name, COLLATION_NAME AS collation_name, COLUMN_COMMENT AS column_comment, COLUMN_TYPE AS column_type, COLUMN_KEY AS column_key, EXTRA AS extra FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? ORDER BY TABLE_NAME ASC, ORDINAL_POSITION ASC"
+	list := make([]*SchemaColumn, 0)
+	if err := s.app.takeAll(ctx, &list, prepare, schema); err != nil {
+		return err
 	}
-	for k, v := range list {
-		v.table = table
-		if v.ColumnComment == nil {
-			list[k].ColumnComment = new(string)
+	for _, c := range list {
+		table, ok := tables[*c.TableName]
+		if !ok {
+			continue
+		}
+		c.table = table
+		if c.ColumnComment == nil {
+			c.ColumnComment = new(string)
+		}
+		if c.ColumnKey != nil && *c.ColumnKey == "PRI" && !table.isView() {
+			table.TablePrimaryKey = append(table.TablePrimaryKey, *c.ColumnName)
 		}
+		c.enum = columnEnum(c)
+		table.Column = append(table.Column, c)
 	}
-	return
+	return nil
+}
+
+// queryUniqueIndex Query the unique indexes of the tables, the functional indexes and the prefix indexes are ignored.
+func (s *HelperMysql) queryUniqueIndex(ctx context.Context, schema string, tables map[string]*SchemaTable) error {
+	prepare := "SELECT TABLE_NAME AS table_name, INDEX_NAME AS index_name, COLUMN_NAME AS column_name, SUB_PART AS sub_part FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = ? AND NON_UNIQUE = 0 AND INDEX_NAME <> 'PRIMARY' ORDER BY TABLE_NAME ASC, INDEX_NAME ASC, SEQ_IN_INDEX ASC"
+	return s.app.query(ctx, func(rows *sql.Rows) error {
+		indexes := make(map[string][]*SchemaIndex)
+		ignore := make(map[string]struct{})
+		table, name, column, subPart := "", "", sql.NullString{}, sql.NullInt64{}
+		for rows.Next() {
+			if err := rows.Scan(&table, &name, &column, &subPart); err != nil {
+				return err
+			}
+			if !column.Valid || subPart.Valid {
+				ignore[fmt.Sprintf("%s.%s", table, name)] = struct{}{}
+			}
+			list := indexes[table]
+			if length := len(list); length == 0 || list[length-1].IndexName != name {
+				list = append(list, &SchemaIndex{IndexName: name, Unique: true})
+				indexes[table] = list
+			}
+			index := list[len(list)-1]
+			index.Columns = append(index.Columns, column.String)
+		}
+		for table, list := range indexes {
+			tmp, ok := tables[table]
+			if !ok || tmp.isView() {
+				continue
+			}
+			for _, v := range list {
+				if _, ok = ignore[fmt.Sprintf("%s.%s", table, v.IndexName)]; !ok {
+					tmp.TableUniqueIndex = append(tmp.TableUniqueIndex, v)
+				}
+			}
+		}
+		return nil
+	}, prepare, schema)
+}
+
+// queryForeignKey Query the foreign keys of the tables.
+func (s *HelperMysql) queryForeignKey(ctx context.Context, schema string, tables map[string]*SchemaTable) error {
+	prepare := "SELECT k.TABLE_NAME AS table_name, k.CONSTRAINT_NAME AS constraint_name, k.COLUMN_NAME AS column_name, k.REFERENCED_TABLE_SCHEMA AS referenced_table_schema, k.REFERENCED_TABLE_NAME AS referenced_table_name, k.REFERENCED_COLUMN_NAME AS referenced_column_name FROM information_schema.KEY_COLUMN_USAGE k JOIN information_schema.REFERENTIAL_CONSTRAINTS r ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA AND r.TABLE_NAME = k.TABLE_NAME AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME WHERE k.TABLE_SCHEMA = ? ORDER BY k.TABLE_NAME ASC, k.CONSTRAINT_NAME ASC, k.ORDINAL_POSITION ASC"
+	return s.app.query(ctx, func(rows *sql.Rows) error {
+		table, name, column, referencedSchema, referencedTable, referencedColumn := "", "", "", "", "", ""
+		for rows.Next() {
+			if err := rows.Scan(&table, &name, &column, &referencedSchema, &referencedTable, &referencedColumn); err != nil {
+				return err
+			}
+			tmp, ok := tables[table]
+			if !ok || tmp.isView() {
+				continue
+			}
+			length := len(tmp.TableForeignKey)
+			if length == 0 || tmp.TableForeignKey[length-1].ConstraintName != name {
+				tmp.TableForeignKey = append(tmp.TableForeignKey, &SchemaForeignKey{
+					ConstraintName:   name,
+					ReferencedSchema: referencedSchema,
+					ReferencedTable:  referencedTable,
+				})
+				length++
+			}
+			foreignKey := tmp.TableForeignKey[length-1]
+			foreignKey.Columns = append(foreignKey.Columns, column)
+			foreignKey.ReferencedColumns = append(foreignKey.ReferencedColumns, referencedColumn)
+		}
+		return nil
+	}, prepare, schema)
 }
 
 func (s *HelperMysql) GetAllTable() []*SchemaTable {
 	return s.tables
 }
 
-func (s *HelperMysql) QueryTableDefineSql(table *SchemaTable) error {
+func (s *HelperMysql) QueryTableDefineSql(ctx context.Context, table *SchemaTable) error {
+	if table.isView() {
+		return s.queryViewDefineSql(ctx, table)
+	}
 	for _, c := range table.Column {
 		if c.Extra != nil && strings.ToLower(*c.Extra) == "auto_increment" {
 			table.TableFieldSerial = *c.ColumnName
@@ -76,7 +223,7 @@ func (s *HelperMysql) QueryTableDefineSql(table *SchemaTable) error {
 	}
 	prepare := fmt.Sprintf("SHOW CREATE TABLE %s.%s", *table.TableSchema, *table.TableName)
 	name, result := "", ""
-	err := s.app.way.Query(func(rows *sql.Rows) error {
+	err := s.app.query(ctx, func(rows *sql.Rows) error {
 		for rows.Next() {
 			if err := rows.Scan(&name, &result); err != nil {
 				return err
@@ -89,5 +236,55 @@ func (s *HelperMysql) QueryTableDefineSql(table *SchemaTable) error {
 	}
 	table.DDL = strings.ReplaceAll(result, "CREATE TABLE", "CREATE TABLE IF NOT EXISTS")
 	table.DDL = autoIncrementRegexpReplace.ReplaceAllString(table.DDL, "${1}=1")
+	// the partitioning clause is output as the executable comment
+	table.DDL = partitionRegexpReplace.ReplaceAllString(table.DDL, "${1}")
+	return nil
+}
+
+// queryPartition Query the partitions of the partitioned tables, the sub partitions are ignored.
+func (s *HelperMysql) queryPartition(ctx context.Context, schema string, tables map[string]*SchemaTable) error {
+	prepare := "SELECT TABLE_NAME AS table_name, PARTITION_NAME AS partition_name, PARTITION_METHOD AS partition_method, PARTITION_EXPRESSION AS partition_expression, PARTITION_DESCRIPTION AS partition_description FROM information_schema.PARTITIONS WHERE TABLE_SCHEMA = ? AND PARTITION_NAME IS NOT NULL AND ( SUBPARTITION_ORDINAL_POSITION IS NULL OR SUBPARTITION_ORDINAL_POSITION = 1 ) ORDER BY TABLE_NAME ASC, PARTITION_ORDINAL_POSITION ASC"
+	return s.app.query(ctx, func(rows *sql.Rows) error {
+		name, partition, method, expression, description := "", "", "", sql.NullString{}, sql.NullString{}
+		for rows.Next() {
+			if err := rows.Scan(&name, &partition, &method, &expression, &description); err != nil {
+				return err
+			}
+			table, ok := tables[name]
+			if !ok {
+				continue
+			}
+			table.PartitionKey = fmt.Sprintf("%s (%s)", method, expression.String)
+			tmp := &SchemaPartition{PartitionName: partition}
+			switch {
+			case strings.HasPrefix(method, "RANGE") && description.String == "MAXVALUE":
+				tmp.PartitionBound = "VALUES LESS THAN MAXVALUE"
+			case strings.HasPrefix(method, "RANGE"):
+				tmp.PartitionBound = fmt.Sprintf("VALUES LESS THAN (%s)", description.String)
+			case strings.HasPrefix(method, "LIST"):
+				tmp.PartitionBound = fmt.Sprintf("VALUES IN (%s)", description.String)
+			}
+			table.Partitions = append(table.Partitions, tmp)
+		}
+		return nil
+	}, prepare, schema)
+}
+
+func (s *HelperMysql) queryViewDefineSql(ctx context.Context, table *SchemaTable) error {
+	prepare := fmt.Sprintf("SHOW CREATE VIEW %s.%s", *table.TableSchema, *table.TableName)
+	name, result, characterSetClient, collationConnection := "", "", "", ""
+	err := s.app.query(ctx, func(rows *sql.Rows) error {
+		for rows.Next() {
+			if err := rows.Scan(&name, &result, &characterSetClient, &collationConnection); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, prepare)
+	if err != nil {
+		return err
+	}
+	// remove ALGORITHM, DEFINER and SQL SECURITY
+	table.DDL = createViewRegexpReplace.ReplaceAllString(result, "CREATE OR REPLACE VIEW ")
 	return nil
 }
@@ -1,87 +1,154 @@
 package app
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"regexp"
 	"strings"
-	"sync"
 )
 
 type HelperPgsql struct {
 	app    *App
 	tables []*SchemaTable
+
+	constraints map[string][]*pgsqlConstraint // 表名 => 表约束
+	indexes     map[string][]*pgsqlIndex      // 表名 => 表索引
+	sequences   map[string]*pgsqlSequence     // 模式名.序列名 => 序列
+}
+
+// pgsqlConstraint 表约束
+type pgsqlConstraint struct {
+	name       string // 约束名称
+	kind       string // 约束类型 p:主键 u:唯一 f:外键 c:检查 x:排他
+	definition string // 约束定义 如: PRIMARY KEY (id)
+	comment    string // 约束注释
+}
+
+// pgsqlIndex 表索引
+type pgsqlIndex struct {
+	name       string // 索引名称
+	definition string // 索引定义 如: CREATE INDEX account_name ON public.account USING btree (name)
+	constraint bool   // 是否为主键,唯一,排他约束创建的索引 建表时已经创建
+	comment    string // 索引注释
+	unique     bool   // 是否唯一
+	primary    bool   // 是否为主键
+	method     string // 索引方法 如: btree, hash, gin
+	columns    string // 索引字段(不包括表达式) 以 \x1f 分隔
+}
+
+// pgsqlSequence 序列
+type pgsqlSequence struct {
+	dataType  string // 数据类型 如: bigint
+	start     int64  // 起始值
+	increment int64  // 步长
+	min       int64  // 最小值
+	max       int64  // 最大值
+	cache     int64  // 缓存数量
+	cycle     bool   // 是否循环
 }
 
 func NewPgsql(app *App) Helper {
 	return &HelperPgsql{app: app}
 }
 
-func (s *HelperPgsql) QueryAllTable() (err error) {
+// QueryAllTable Query all tables of the schema, the catalog is queried in bulk and grouped by table name, the number of queries does not grow with the number of tables.
+func (s *HelperPgsql) QueryAllTable(ctx context.Context) (err error) {
 	schema := s.app.cfg.TableSchemaName
-	prepare := "SELECT table_schema, table_name FROM information_schema.tables WHERE ( table_schema = ? AND table_type = 'BASE TABLE' ) ORDER BY table_name ASC"
-	if err = s.app.way.TakeAll(&s.tables, prepare, schema); err != nil {
+	prepare := "SELECT table_schema, table_name, table_type FROM information_schema.tables WHERE ( table_schema = ? AND table_type IN ( 'BASE TABLE', 'VIEW' ) ) UNION ALL SELECT schemaname AS table_schema, matviewname AS table_name, 'MATERIALIZED VIEW' AS table_type FROM pg_matviews WHERE ( schemaname = ? ) ORDER BY table_name ASC"
+	if err = s.app.takeAll(ctx, &s.tables, prepare, schema, schema); err != nil {
 		return
 	}
-	once := &sync.Once{}
-	wg := &sync.WaitGroup{}
+	tables := make(map[string]*SchemaTable, len(s.tables))
 	for _, table := range s.tables {
 		table.app = s.app
-		wg.Add(1)
-		go func(table *SchemaTable) {
-			defer wg.Done()
-			columns, qer := s.queryColumns(schema, table)
-			if qer != nil {
-				once.Do(func() { err = qer })
-				return
-			}
-			table.Column = columns
-			if qer = s.queryComment(schema, table); qer != nil {
-				once.Do(func() { err = qer })
-			}
-		}(table)
+		tables[*table.TableName] = table
 	}
-	wg.Wait()
-	return
-}
-
-func (s *HelperPgsql) queryComment(schema string, table *SchemaTable) (err error) {
-	if table.TableName == nil || schema == "" {
+	if err = s.queryPartition(ctx, schema, tables); err != nil {
 		return
 	}
-	prepare := "SELECT cast(obj_description(relfilenode, 'pg_class') AS VARCHAR) AS table_comment FROM pg_tables LEFT OUTER JOIN pg_class ON pg_tables.tablename = pg_class.relname WHERE ( pg_tables.schemaname = ? AND pg_tables.tablename = ? ) ORDER BY pg_tables.schemaname ASC LIMIT 1;"
-	if err = s.app.way.Query(func(rows *sql.Rows) (err error) {
-		if !rows.Next() {
-			return
-		}
-		comment := sql.NullString{}
-		if err = rows.Scan(&comment); err != nil {
-			return
-		}
-		if comment.Valid {
-			table.TableComment = &comment.String
-		} else {
-			table.TableComment = new(string)
-		}
+	enums, err := s.queryEnum(ctx)
+	if err != nil {
 		return
-	}, prepare, schema, *table.TableName); err != nil {
+	}
+	if err = s.queryColumns(ctx, schema, tables, enums); err != nil {
+		return
+	}
+	if err = s.app.parallel(
+		ctx,
+		func(ctx context.Context) error { return s.queryComment(ctx, schema, tables) },
+		func(ctx context.Context) error { return s.queryPrimaryKey(ctx, schema, tables) },
+		func(ctx context.Context) error { return s.queryUniqueIndex(ctx, schema, tables) },
+		func(ctx context.Context) error { return s.queryForeignKey(ctx, schema, tables) },
+		func(ctx context.Context) error { return s.queryPartitionKey(ctx, schema, tables) },
+		func(ctx context.Context) error { return s.queryConstraint(ctx, schema) },
+		func(ctx context.Context) error { return s.queryIndex(ctx, schema) },
+		func(ctx context.Context) error { return s.querySequence(ctx) },
+	); err != nil {
 		return
 	}
-	if table.TableComment == nil {
-		table.TableComment = new(string)
+	for _, table := range s.tables {
+		if table.TableComment == nil {
+			table.TableComment = new(string)
+		}
+		for _, v := range s.indexes[*table.TableName] {
+			index := &SchemaIndex{IndexName: v.name, Unique: v.unique, Primary: v.primary, Method: v.method}
+			for _, column := range strings.Split(v.columns, "\x1f") {
+				if column != "" {
+					index.Columns = append(index.Columns, column)
+				}
+			}
+			table.TableIndex = append(table.TableIndex, index)
+		}
+		for _, v := range s.constraints[*table.TableName] {
+			if v.kind == "c" {
+				table.TableCheck = append(table.TableCheck, &SchemaCheck{
+					ConstraintName: v.name,
+					CheckClause:    checkClause(v.definition, false),
+				})
+			}
+		}
+		for _, c := range table.Column {
+			if c.ColumnComment == nil {
+				c.ColumnComment = new(string)
+			}
+		}
 	}
 	return
 }
 
-func (s *HelperPgsql) queryColumns(schema string, table *SchemaTable) (list []*SchemaColumn, err error) {
-	if schema == "" || table == nil || table.TableName == nil || *table.TableName == "" {
+// queryEnum Query all enum types, the key of result is schema.name
+func (s *HelperPgsql) queryEnum(ctx context.Context) (result map[string]*SchemaEnum, err error) {
+	result = make(map[string]*SchemaEnum)
+	prepare := "SELECT n.nspname AS enum_schema, t.typname AS enum_name, e.enumlabel AS enum_value FROM pg_type t JOIN pg_enum e ON e.enumtypid = t.oid JOIN pg_namespace n ON n.oid = t.typnamespace ORDER BY n.nspname ASC, t.typname ASC, e.enumsortorder ASC"
+	err = s.app.query(ctx, func(rows *sql.Rows) (err error) {
+		schema, name, value := "", "", ""
+		for rows.Next() {
+			if err = rows.Scan(&schema, &name, &value); err != nil {
+				return
+			}
+			key := fmt.Sprintf("%s.%s", schema, name)
+			enum, ok := result[key]
+			if !ok {
+				enum = &SchemaEnum{EnumSchema: schema, EnumName: name}
+				if schema == s.app.cfg.TableSchemaName {
+					enum.prefix = s.app.cfg.tableSchemaPrefix
+				}
+				result[key] = enum
+			}
+			enum.Values = append(enum.Values, value)
+		}
 		return
-	}
-	prepare := "SELECT table_schema, table_name, column_name, ordinal_position, column_default, is_nullable, data_type, character_maximum_length, character_octet_length, numeric_precision, numeric_scale, character_set_name, collation_name FROM information_schema.columns WHERE ( table_schema = ? AND table_name = ? ) ORDER BY ordinal_position ASC"
-	err = s.app.way.Query(func(rows *sql.Rows) (err error) {
+	}, prepare)
+	return
+}
+
+// queryColumns Query the columns of all tables, views and materialized views in the schema.
+func (s *HelperPgsql) queryColumns(ctx context.Context, schema string, tables map[string]*SchemaTable, enums map[string]*SchemaEnum) error {
+	scan := func(rows *sql.Rows) error {
 		for rows.Next() {
 			tmp := &SchemaColumn{}
-			if err = rows.Scan(
+			if err := rows.Scan(
 				&tmp.TableSchema,
 				&tmp.TableName,
 				&tmp.ColumnName,
@@ -94,85 +161,479 @@ func (s *HelperPgsql) queryColumns(schema string, table *SchemaTable) (list []*S
 				&tmp.NumericPrecision,
 				&tmp.NumericScale,
 				&tmp.CharacterSetName,
-				&tmp.ColumnComment,
+				&tmp.CollationName,
+				&tmp.UdtSchema,
+				&tmp.UdtName,
+				&tmp.IsGenerated,
+				&tmp.IsIdentity,
+				&tmp.IdentityGeneration,
+				&tmp.GenerationExpression,
+				&tmp.ColumnType,
 			); err != nil {
-				return
+				return err
 			}
-			list = append(list, tmp)
+			table, ok := tables[*tmp.TableName]
+			if !ok {
+				continue
+			}
+			tmp.table = table
+			if tmp.DataType != nil && *tmp.DataType == "USER-DEFINED" && tmp.UdtSchema != nil && tmp.UdtName != nil {
+				tmp.enum = enums[fmt.Sprintf("%s.%s", *tmp.UdtSchema, *tmp.UdtName)]
+			}
+			table.Column = append(table.Column, tmp)
 		}
-		return
-	}, prepare, schema, *table.TableName)
-	if err != nil {
-		return
+		return nil
 	}
-	for k, v := range list {
-		v.table = table
-		if v.ColumnName == nil || *v.ColumnName == "" {
-			continue
+	prepare := "SELECT c.table_schema, c.table_name, c.column_name, c.ordinal_position, c.column_default, c.is_nullable, c.data_type, c.character_maximum_length, c.character_octet_length, c.numeric_precision, c.numeric_scale, c.character_set_name, c.collation_name, c.udt_schema, c.udt_name, c.is_generated, c.is_identity, c.identity_generation, c.generation_expression, format_type(a.atttypid, a.atttypmod) AS column_type FROM information_schema.columns c JOIN pg_attribute a ON a.attrelid = format('%I.%I', c.table_schema, c.table_name)::regclass AND a.attname = c.column_name WHERE ( c.table_schema = ? ) ORDER BY c.table_name ASC, c.ordinal_position ASC"
+	if err := s.app.query(ctx, scan, prepare, schema); err != nil {
+		return err
+	}
+	// materialized views are not in information_schema.columns, same as information_schema.columns
+	prepare = `SELECT n.nspname AS table_schema, c.relname AS table_name, a.attname AS column_name, a.attnum AS ordinal_position, pg_get_expr(d.adbin, d.adrelid) AS column_default, CASE WHEN a.attnotnull THEN 'NO' ELSE 'YES' END AS is_nullable, CASE WHEN t.typcategory = 'A' THEN 'ARRAY' WHEN t.typtype = 'e' THEN 'USER-DEFINED' ELSE format_type(a.atttypid, NULL) END AS data_type, CASE WHEN a.atttypid IN ( 1042, 1043 ) AND a.atttypmod > 0 THEN a.atttypmod - 4 END AS character_maximum_length, CASE WHEN a.atttypid IN ( 1042, 1043 ) AND a.atttypmod > 0 THEN ( a.atttypmod - 4 ) * 4 END AS character_octet_length, CASE WHEN a.atttypid = 1700 AND a.atttypmod > 0 THEN ( ( a.atttypmod - 4 ) >> 16 ) & 65535 END AS numeric_precision, CASE WHEN a.atttypid = 1700 AND a.atttypmod > 0 THEN ( a.atttypmod - 4 ) & 65535 END AS numeric_scale, NULL AS character_set_name, NULL AS collation_name, tn.nspname AS udt_schema, t.typname AS udt_name, 'NEVER' AS is_generated, 'NO' AS is_identity, NULL AS identity_generation, NULL AS generation_expression, format_type(a.atttypid, a.atttypmod) AS column_type FROM pg_attribute a JOIN pg_class c ON c.oid = a.attrelid JOIN pg_namespace n ON n.oid = c.relnamespace JOIN pg_type t ON t.oid = a.atttypid JOIN pg_namespace tn ON tn.oid = t.typnamespace LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum WHERE ( n.nspname = ? AND c.relkind = 'm' AND a.attnum > 0 AND NOT a.attisdropped ) ORDER BY c.relname ASC, a.attnum ASC`
+	return s.app.query(ctx, scan, prepare, schema)
+}
+
+// queryComment Query the comments of all tables and columns in the schema.
+func (s *HelperPgsql) queryComment(ctx context.Context, schema string, tables map[string]*SchemaTable) error {
+	prepare := "SELECT c.relname AS table_name, COALESCE(a.attname, '') AS column_name, d.description FROM pg_description d JOIN pg_class c ON c.oid = d.objoid JOIN pg_namespace n ON n.oid = c.relnamespace LEFT JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = d.objsubid AND d.objsubid > 0 WHERE ( n.nspname = ? AND d.classoid = 'pg_class'::regclass )"
+	return s.app.query(ctx, func(rows *sql.Rows) error {
+		name, column := "", ""
+		for rows.Next() {
+			comment := ""
+			if err := rows.Scan(&name, &column, &comment); err != nil {
+				return err
+			}
+			table, ok := tables[name]
+			if !ok {
+				continue
+			}
+			if column == "" {
+				table.TableComment = &comment
+				continue
+			}
+			for _, c := range table.Column {
+				if *c.ColumnName == column {
+					c.ColumnComment = &comment
+					break
+				}
+			}
 		}
-		// query column comment
-		// SELECT a.attnum AS id, a.attname AS column_name, t.typname AS type_basic, SUBSTRING(FORMAT_TYPE(a.atttypid, a.atttypmod) FROM '(.*)') AS type_sql, a.attnotnull AS not_null, d.description AS comment FROM pg_class c, pg_attribute a, pg_type t, pg_description d WHERE ( c.relname = 'TABLE_NAME' AND a.attnum > 0 AND a.attrelid = c.oid AND a.atttypid = t.oid AND d.objoid = a.attrelid AND d.objsubid = a.attnum ) ORDER BY id ASC;
-		err = s.app.way.Query(func(rows *sql.Rows) (err error) {
-			if !rows.Next() {
-				return
+		return nil
+	}, prepare, schema)
+}
+
+// queryPrimaryKey Query the columns of primary key constraint in order.
+func (s *HelperPgsql) queryPrimaryKey(ctx context.Context, schema string, tables map[string]*SchemaTable) error {
+	prepare := "SELECT c.relname AS table_name, a.attname AS column_name FROM pg_index i JOIN pg_class c ON c.oid = i.indrelid JOIN pg_namespace n ON n.oid = c.relnamespace JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = ANY(i.indkey) WHERE ( n.nspname = ? AND i.indisprimary ) ORDER BY c.relname ASC, array_position(i.indkey::int2[], a.attnum) ASC"
+	return s.app.query(ctx, func(rows *sql.Rows) error {
+		name, column := "", ""
+		for rows.Next() {
+			if err := rows.Scan(&name, &column); err != nil {
+				return err
 			}
-			tmp := ""
-			if err = rows.Scan(&tmp); err != nil {
-				return
+			table, ok := tables[name]
+			if !ok || table.isView() {
+				continue
+			}
+			table.TablePrimaryKey = append(table.TablePrimaryKey, column)
+			for _, c := range table.Column {
+				if *c.ColumnName == column {
+					key := "PRI"
+					c.ColumnKey = &key
+					break
+				}
+			}
+		}
+		return nil
+	}, prepare, schema)
+}
+
+// queryUniqueIndex Query the unique constraints and unique indexes of the tables, the expression indexes and the partial indexes are ignored.
+func (s *HelperPgsql) queryUniqueIndex(ctx context.Context, schema string, tables map[string]*SchemaTable) error {
+	prepare := "SELECT c.relname AS table_name, ic.relname AS index_name, a.attname AS column_name FROM pg_index i JOIN pg_class c ON c.oid = i.indrelid JOIN pg_namespace n ON n.oid = c.relnamespace JOIN pg_class ic ON ic.oid = i.indexrelid CROSS JOIN LATERAL unnest(i.indkey::int2[]) WITH ORDINALITY AS k(attnum, ordinal) JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = k.attnum WHERE ( n.nspname = ? AND i.indisunique AND NOT i.indisprimary AND i.indpred IS NULL AND i.indexprs IS NULL AND k.ordinal <= i.indnkeyatts ) ORDER BY c.relname ASC, ic.relname ASC, k.ordinal ASC"
+	return s.app.query(ctx, func(rows *sql.Rows) error {
+		table, name, column := "", "", ""
+		for rows.Next() {
+			if err := rows.Scan(&table, &name, &column); err != nil {
+				return err
+			}
+			tmp, ok := tables[table]
+			if !ok || tmp.isView() {
+				continue
 			}
-			list[k].ColumnComment = &tmp
-			return
-		}, "SELECT d.description AS column_comment FROM pg_class c, pg_attribute a, pg_type t, pg_description d WHERE ( c.relname = ? AND a.attname = ? AND a.attnum > 0 AND a.attrelid = c.oid AND a.atttypid = t.oid AND d.objoid = a.attrelid AND d.objsubid = a.attnum ) ORDER BY a.attnum ASC LIMIT 1;", *table.TableName, *v.ColumnName)
-		if err != nil {
-			return
+			length := len(tmp.TableUniqueIndex)
+			if length == 0 || tmp.TableUniqueIndex[length-1].IndexName != name {
+				tmp.TableUniqueIndex = append(tmp.TableUniqueIndex, &SchemaIndex{IndexName: name, Unique: true})
+				length++
+			}
+			index := tmp.TableUniqueIndex[length-1]
+			index.Columns = append(index.Columns, column)
 		}
-		if v.ColumnComment == nil {
-			v.ColumnComment = new(string)
+		return nil
+	}, prepare, schema)
+}
+
+// queryForeignKey Query the foreign keys of the tables.
+func (s *HelperPgsql) queryForeignKey(ctx context.Context, schema string, tables map[string]*SchemaTable) error {
+	prepare := "SELECT c.relname AS table_name, con.conname AS constraint_name, a.attname AS column_name, fn.nspname AS referenced_table_schema, fc.relname AS referenced_table_name, fa.attname AS referenced_column_name FROM pg_constraint con JOIN pg_class c ON c.oid = con.conrelid JOIN pg_namespace n ON n.oid = c.relnamespace JOIN pg_class fc ON fc.oid = con.confrelid JOIN pg_namespace fn ON fn.oid = fc.relnamespace CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, referenced_attnum, ordinal) JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum JOIN pg_attribute fa ON fa.attrelid = con.confrelid AND fa.attnum = k.referenced_attnum WHERE ( n.nspname = ? AND con.contype = 'f' ) ORDER BY c.relname ASC, con.conname ASC, k.ordinal ASC"
+	return s.app.query(ctx, func(rows *sql.Rows) error {
+		table, name, column, referencedSchema, referencedTable, referencedColumn := "", "", "", "", "", ""
+		for rows.Next() {
+			if err := rows.Scan(&table, &name, &column, &referencedSchema, &referencedTable, &referencedColumn); err != nil {
+				return err
+			}
+			tmp, ok := tables[table]
+			if !ok || tmp.isView() {
+				continue
+			}
+			length := len(tmp.TableForeignKey)
+			if length == 0 || tmp.TableForeignKey[length-1].ConstraintName != name {
+				tmp.TableForeignKey = append(tmp.TableForeignKey, &SchemaForeignKey{
+					ConstraintName:   name,
+					ReferencedSchema: referencedSchema,
+					ReferencedTable:  referencedTable,
+				})
+				length++
+			}
+			foreignKey := tmp.TableForeignKey[length-1]
+			foreignKey.Columns = append(foreignKey.Columns, column)
+			foreignKey.ReferencedColumns = append(foreignKey.ReferencedColumns, referencedColumn)
 		}
-	}
-	return
+		return nil
+	}, prepare, schema)
 }
 
 func (s *HelperPgsql) GetAllTable() []*SchemaTable {
 	return s.tables
 }
 
-var pgSeq = regexp.MustCompile(`^nextval\('([A-Za-z0-9_]+)'::regclass\)$`)
+// pgSeq The sequence of column default value, the name may be qualified by schema, like: nextval('billing.seq'::regclass)
+var pgSeq = regexp.MustCompile(`^nextval\('([A-Za-z0-9_."]+)'::regclass\)$`)
 
-func (s *HelperPgsql) QueryTableDefineSql(table *SchemaTable) error {
-	var createSequence string
+func (s *HelperPgsql) QueryTableDefineSql(ctx context.Context, table *SchemaTable) error {
+	if table.isView() {
+		return s.queryViewDefineSql(ctx, table)
+	}
+	// the ddl is built from the catalog, nothing is created in the database
+	statements := make([]string, 0, 8)
+	enums := make(map[*SchemaEnum]struct{})
+	for _, c := range table.Column {
+		if c.enum == nil {
+			continue
+		}
+		if _, ok := enums[c.enum]; !ok {
+			enums[c.enum] = struct{}{}
+			statements = append(statements, pgEnumDefine(*table.TableSchema, c.enum))
+		}
+	}
+	sequences := make(map[string]struct{})
+	lines := make([]string, 0, len(table.Column))
 	for _, c := range table.Column {
+		lines = append(lines, pgColumnDefine(c))
+		if c.IsIdentity != nil && strings.ToUpper(*c.IsIdentity) == "YES" {
+			// the sequence of identity column is created with the table
+			table.TableFieldSerial = *c.ColumnName
+			continue
+		}
 		if c.ColumnDefault == nil {
 			continue
 		}
-		if strings.Contains(*c.ColumnDefault, "\"") {
-			*c.ColumnDefault = strings.ReplaceAll(*c.ColumnDefault, "\"", "")
+		if result := pgSeq.FindStringSubmatch(*c.ColumnDefault); len(result) == 2 && result[1] != "" {
+			table.TableFieldSerial = *c.ColumnName
+			if _, ok := sequences[result[1]]; !ok {
+				sequences[result[1]] = struct{}{}
+				statements = append(statements, s.sequenceDefine(*table.TableSchema, result[1]))
+			}
 		}
-		if pgSeq.MatchString(*c.ColumnDefault) {
-			result := pgSeq.FindAllStringSubmatch(*c.ColumnDefault, -1)
-			if len(result) == 1 && len(result[0]) == 2 && result[0][1] != "" {
-				createSequence = fmt.Sprintf("CREATE SEQUENCE IF NOT EXISTS %s START 1;\n", result[0][1])
-				table.TableFieldSerial = *c.ColumnName
+	}
+	for _, v := range s.constraints[*table.TableName] {
+		lines = append(lines, fmt.Sprintf("  CONSTRAINT %s %s", pgQuoteIdent(v.name), v.definition))
+	}
+	name := pgQuoteIdent(*table.TableName)
+	create := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n%s\n)", name, strings.Join(lines, ",\n"))
+	if table.PartitionKey != "" {
+		create = fmt.Sprintf("%s PARTITION BY %s", create, table.PartitionKey)
+	}
+	statements = append(statements, create+";")
+	statements = append(statements, pgPartitionDefine(name, table.Partitions)...)
+	for _, v := range s.indexes[*table.TableName] {
+		if v.constraint {
+			continue
+		}
+		definition := v.definition
+		// the table of index is not qualified by schema, same as the table
+		// the index of partitioned table is created without ONLY, so it cascades to the partitions created above
+		only := " ON ONLY "
+		if table.PartitionKey != "" {
+			only = " ON "
+		}
+		for _, schema := range []string{*table.TableSchema, pgQuoteIdent(*table.TableSchema)} {
+			definition = strings.Replace(definition, fmt.Sprintf(" ON %s.", schema), " ON ", 1)
+			definition = strings.Replace(definition, fmt.Sprintf(" ON ONLY %s.", schema), only, 1)
+		}
+		definition = strings.Replace(definition, "CREATE INDEX", "CREATE INDEX IF NOT EXISTS", 1)
+		definition = strings.Replace(definition, "CREATE UNIQUE INDEX", "CREATE UNIQUE INDEX IF NOT EXISTS", 1)
+		statements = append(statements, definition+";")
+	}
+	if comment := table.comment(); comment != "" {
+		statements = append(statements, fmt.Sprintf("COMMENT ON TABLE %s IS %s;", name, pgQuoteLiteral(comment)))
+	}
+	for _, c := range table.Column {
+		if c.ColumnComment != nil && *c.ColumnComment != "" {
+			statements = append(statements, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", name, pgQuoteIdent(*c.ColumnName), pgQuoteLiteral(*c.ColumnComment)))
+		}
+	}
+	for _, v := range s.indexes[*table.TableName] {
+		if v.comment != "" {
+			statements = append(statements, fmt.Sprintf("COMMENT ON INDEX %s IS %s;", pgQuoteIdent(v.name), pgQuoteLiteral(v.comment)))
+		}
+	}
+	for _, v := range s.constraints[*table.TableName] {
+		if v.comment != "" {
+			statements = append(statements, fmt.Sprintf("COMMENT ON CONSTRAINT %s ON %s IS %s;", pgQuoteIdent(v.name), name, pgQuoteLiteral(v.comment)))
+		}
+	}
+	table.DDL = strings.Join(statements, "\n") + "\n"
+	return nil
+}
+
+// pgColumnDefine The definition of column in CREATE TABLE, like: "name" character varying(32) NOT NULL DEFAULT 'none'::character varying
+func pgColumnDefine(c *SchemaColumn) string {
+	columnType := ""
+	switch {
+	case c.ColumnType != nil && *c.ColumnType != "":
+		columnType = *c.ColumnType
+	case c.DataType != nil:
+		columnType = *c.DataType
+	}
+	result := fmt.Sprintf("  %s %s", pgQuoteIdent(*c.ColumnName), columnType)
+	if c.IsNullable != nil && strings.ToUpper(*c.IsNullable) == "NO" {
+		result += " NOT NULL"
+	}
+	switch {
+	case c.GenerationExpression != nil && *c.GenerationExpression != "":
+		result = fmt.Sprintf("%s GENERATED ALWAYS AS (%s) STORED", result, *c.GenerationExpression)
+	case c.ColumnDefault != nil:
+		result = fmt.Sprintf("%s DEFAULT %s", result, *c.ColumnDefault)
+	}
+	if c.IsIdentity != nil && strings.ToUpper(*c.IsIdentity) == "YES" && c.IdentityGeneration != nil {
+		result = fmt.Sprintf("%s GENERATED %s AS IDENTITY", result, strings.ToUpper(*c.IdentityGeneration))
+	}
+	return result
+}
+
+// pgEnumDefine The CREATE TYPE statement of the enum type used by column, the type of other schema is qualified by its schema.
+// CREATE TYPE does not support IF NOT EXISTS, the type which is shared by tables is created only once.
+func pgEnumDefine(schema string, enum *SchemaEnum) string {
+	name := pgQuoteIdent(enum.EnumName)
+	if enum.EnumSchema != schema {
+		name = pgQuoteName(fmt.Sprintf("%s.%s", enum.EnumSchema, enum.EnumName))
+	}
+	values := make([]string, 0, len(enum.Values))
+	for _, v := range enum.Values {
+		values = append(values, pgQuoteLiteral(v))
+	}
+	return fmt.Sprintf("DO $$ BEGIN CREATE TYPE %s AS ENUM (%s); EXCEPTION WHEN duplicate_object THEN NULL; END $$;", name, strings.Join(values, ", "))
+}
+
+// sequenceDefine The CREATE SEQUENCE statement of the sequence used by column default value, the unqualified name is looked up in the schema of table first.
+func (s *HelperPgsql) sequenceDefine(schema string, name string) string {
+	parts := strings.Split(strings.ReplaceAll(name, `"`, ""), ".")
+	sequence, ok := (*pgsqlSequence)(nil), false
+	switch len(parts) {
+	case 2:
+		sequence, ok = s.sequences[fmt.Sprintf("%s.%s", parts[0], parts[1])]
+	case 1:
+		if sequence, ok = s.sequences[fmt.Sprintf("%s.%s", schema, parts[0])]; !ok {
+			sequence, ok = s.sequences[fmt.Sprintf("public.%s", parts[0])]
+		}
+	}
+	if !ok {
+		return fmt.Sprintf("CREATE SEQUENCE IF NOT EXISTS %s START 1;", pgQuoteName(name))
+	}
+	cycle := ""
+	if sequence.cycle {
+		cycle = " CYCLE"
+	}
+	return fmt.Sprintf("CREATE SEQUENCE IF NOT EXISTS %s AS %s INCREMENT BY %d MINVALUE %d MAXVALUE %d START WITH %d CACHE %d%s;", pgQuoteName(name), sequence.dataType, sequence.increment, sequence.min, sequence.max, sequence.start, sequence.cache, cycle)
+}
+
+// pgPartitionDefine The CREATE TABLE ... PARTITION OF statements of the partitions, the sub partitions follow their parent.
+func pgPartitionDefine(parent string, partitions []*SchemaPartition) []string {
+	result := make([]string, 0, len(partitions))
+	for _, v := range partitions {
+		name := pgQuoteIdent(v.PartitionName)
+		tmp := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s %s", name, parent, v.PartitionBound)
+		if v.PartitionKey != "" {
+			tmp = fmt.Sprintf("%s PARTITION BY %s", tmp, v.PartitionKey)
+		}
+		result = append(result, tmp+";")
+		result = append(result, pgPartitionDefine(name, v.Partitions)...)
+	}
+	return result
+}
+
+// queryPartitionKey Query the partition keys of the partitioned tables.
+func (s *HelperPgsql) queryPartitionKey(ctx context.Context, schema string, tables map[string]*SchemaTable) error {
+	prepare := "SELECT c.relname AS table_name, pg_get_partkeydef(c.oid) AS partition_key FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace WHERE ( n.nspname = ? AND c.relkind = 'p' )"
+	return s.app.query(ctx, func(rows *sql.Rows) error {
+		name, partitionKey := "", ""
+		for rows.Next() {
+			if err := rows.Scan(&name, &partitionKey); err != nil {
+				return err
+			}
+			if table, ok := tables[name]; ok {
+				table.PartitionKey = partitionKey
 			}
 		}
+		return nil
+	}, prepare, schema)
+}
+
+// queryPartition Query the partitions of the partitioned tables, the partitions are removed from the tables and attached to their parent.
+func (s *HelperPgsql) queryPartition(ctx context.Context, schema string, tables map[string]*SchemaTable) error {
+	prepare := "SELECT p.relname AS parent_name, c.relname AS partition_name, COALESCE(pg_get_expr(c.relpartbound, c.oid), '') AS partition_bound, COALESCE(pg_get_partkeydef(c.oid), '') AS partition_key FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid JOIN pg_class p ON p.oid = i.inhparent JOIN pg_namespace n ON n.oid = c.relnamespace WHERE ( n.nspname = ? AND c.relispartition AND p.relnamespace = c.relnamespace ) ORDER BY p.relname ASC, c.relname ASC"
+	parents := make([]string, 0)
+	partitions := make([]*SchemaPartition, 0)
+	err := s.app.query(ctx, func(rows *sql.Rows) error {
+		parent := ""
+		for rows.Next() {
+			tmp := &SchemaPartition{}
+			if err := rows.Scan(&parent, &tmp.PartitionName, &tmp.PartitionBound, &tmp.PartitionKey); err != nil {
+				return err
+			}
+			parents = append(parents, parent)
+			partitions = append(partitions, tmp)
+		}
+		return nil
+	}, prepare, schema)
+	if err != nil {
+		return err
+	}
+	// the partition may be a partitioned table too
+	partitionMap := make(map[string]*SchemaPartition, len(partitions))
+	for _, v := range partitions {
+		partitionMap[v.PartitionName] = v
+		delete(tables, v.PartitionName)
+	}
+	for i, v := range partitions {
+		if table, ok := tables[parents[i]]; ok {
+			table.Partitions = append(table.Partitions, v)
+			continue
+		}
+		if parent, ok := partitionMap[parents[i]]; ok {
+			parent.Partitions = append(parent.Partitions, v)
+		}
+	}
+	result := s.tables[:0]
+	for _, table := range s.tables {
+		if _, ok := partitionMap[*table.TableName]; !ok {
+			result = append(result, table)
+		}
 	}
-	prepare := fmt.Sprintf("SELECT show_create_table_schema('%s', '%s')", *table.TableSchema, *table.TableName)
+	s.tables = result
+	return nil
+}
+
+// queryConstraint Query the constraints of the tables, the constraints inherited from the parent table are ignored.
+func (s *HelperPgsql) queryConstraint(ctx context.Context, schema string) error {
+	result := make(map[string][]*pgsqlConstraint)
+	prepare := "SELECT c.relname AS table_name, con.conname AS constraint_name, con.contype::text AS constraint_type, pg_get_constraintdef(con.oid) AS constraint_definition, COALESCE(obj_description(con.oid, 'pg_constraint'), '') AS constraint_comment FROM pg_constraint con JOIN pg_class c ON c.oid = con.conrelid JOIN pg_namespace n ON n.oid = c.relnamespace WHERE ( n.nspname = ? AND con.contype IN ( 'p', 'u', 'f', 'c', 'x' ) AND con.conislocal ) ORDER BY c.relname ASC, CASE con.contype WHEN 'p' THEN 1 WHEN 'u' THEN 2 WHEN 'f' THEN 3 WHEN 'c' THEN 4 ELSE 5 END ASC, con.conname ASC"
+	err := s.app.query(ctx, func(rows *sql.Rows) error {
+		table := ""
+		for rows.Next() {
+			tmp := &pgsqlConstraint{}
+			if err := rows.Scan(&table, &tmp.name, &tmp.kind, &tmp.definition, &tmp.comment); err != nil {
+				return err
+			}
+			result[table] = append(result[table], tmp)
+		}
+		return nil
+	}, prepare, schema)
+	s.constraints = result
+	return err
+}
+
+// queryIndex Query the indexes of the tables.
+func (s *HelperPgsql) queryIndex(ctx context.Context, schema string) error {
+	result := make(map[string][]*pgsqlIndex)
+	prepare := "SELECT c.relname AS table_name, ic.relname AS index_name, pg_get_indexdef(i.indexrelid) AS index_definition, EXISTS ( SELECT 1 FROM pg_constraint con WHERE con.conindid = i.indexrelid AND con.conrelid = i.indrelid AND con.contype IN ( 'p', 'u', 'x' ) ) AS index_constraint, COALESCE(obj_description(i.indexrelid, 'pg_class'), '') AS index_comment, i.indisunique AS index_unique, i.indisprimary AS index_primary, am.amname AS index_method, array_to_string(ARRAY( SELECT a.attname FROM generate_series(1, i.indnkeyatts) AS k JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = i.indkey[k - 1] ORDER BY k ), chr(31)) AS index_columns FROM pg_index i JOIN pg_class c ON c.oid = i.indrelid JOIN pg_class ic ON ic.oid = i.indexrelid JOIN pg_am am ON am.oid = ic.relam JOIN pg_namespace n ON n.oid = c.relnamespace WHERE ( n.nspname = ? ) ORDER BY c.relname ASC, ic.relname ASC"
+	err := s.app.query(ctx, func(rows *sql.Rows) error {
+		table := ""
+		for rows.Next() {
+			tmp := &pgsqlIndex{}
+			if err := rows.Scan(&table, &tmp.name, &tmp.definition, &tmp.constraint, &tmp.comment, &tmp.unique, &tmp.primary, &tmp.method, &tmp.columns); err != nil {
+				return err
+			}
+			result[table] = append(result[table], tmp)
+		}
+		return nil
+	}, prepare, schema)
+	s.indexes = result
+	return err
+}
+
+// querySequence Query all sequences, the sequences of other schemas may be used by the column default value.
+func (s *HelperPgsql) querySequence(ctx context.Context) error {
+	result := make(map[string]*pgsqlSequence)
+	prepare := "SELECT n.nspname AS sequence_schema, c.relname AS sequence_name, format_type(s.seqtypid, NULL) AS data_type, s.seqstart, s.seqincrement, s.seqmin, s.seqmax, s.seqcache, s.seqcycle FROM pg_sequence s JOIN pg_class c ON c.oid = s.seqrelid JOIN pg_namespace n ON n.oid = c.relnamespace"
+	err := s.app.query(ctx, func(rows *sql.Rows) error {
+		schema, name := "", ""
+		for rows.Next() {
+			tmp := &pgsqlSequence{}
+			if err := rows.Scan(&schema, &name, &tmp.dataType, &tmp.start, &tmp.increment, &tmp.min, &tmp.max, &tmp.cache, &tmp.cycle); err != nil {
+				return err
+			}
+			result[fmt.Sprintf("%s.%s", schema, name)] = tmp
+		}
+		return nil
+	}, prepare)
+	s.sequences = result
+	return err
+}
+
+// pgQuoteIdent Quote the identifier, like: account => "account"
+func pgQuoteIdent(name string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(name, `"`, `""`))
+}
+
+// pgQuoteLiteral Quote the string literal, the single quote is doubled.
+func pgQuoteLiteral(value string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(value, "'", "''"))
+}
+
+// pgQuoteName Quote the name which may be qualified by schema, like: billing.seq => "billing"."seq"
+func pgQuoteName(name string) string {
+	parts := strings.Split(strings.ReplaceAll(name, `"`, ""), ".")
+	for k, v := range parts {
+		parts[k] = fmt.Sprintf(`"%s"`, v)
+	}
+	return strings.Join(parts, ".")
+}
+
+func (s *HelperPgsql) queryViewDefineSql(ctx context.Context, table *SchemaTable) error {
+	prepare := "SELECT pg_get_viewdef(c.oid, true) FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace WHERE ( n.nspname = ? AND c.relname = ? )"
 	result := ""
-	err := s.app.way.Query(func(rows *sql.Rows) error {
+	err := s.app.query(ctx, func(rows *sql.Rows) error {
 		for rows.Next() {
 			if err := rows.Scan(&result); err != nil {
 				return err
 			}
 		}
 		return nil
-	}, prepare)
+	}, prepare, *table.TableSchema, *table.TableName)
 	if err != nil {
 		return err
 	}
-	result = strings.ReplaceAll(result, "CREATE TABLE", "CREATE TABLE IF NOT EXISTS")
-	result = strings.ReplaceAll(result, "CREATE INDEX", "CREATE INDEX IF NOT EXISTS")
-	result = strings.ReplaceAll(result, "CREATE UNIQUE INDEX", "CREATE UNIQUE INDEX IF NOT EXISTS")
-	result = createSequence + result
-	table.DDL = result
+	result = strings.TrimSuffix(strings.TrimSpace(result), ";")
+	create := "CREATE OR REPLACE VIEW"
+	if *table.TableType == tableTypeMaterializedView {
+		create = "CREATE MATERIALIZED VIEW IF NOT EXISTS"
+	}
+	ddl := fmt.Sprintf("%s \"%s\" AS\n%s;\n", create, *table.TableName, result)
+	if comment := table.comment(); comment != "" {
+		ddl += fmt.Sprintf("COMMENT ON %s \"%s\" IS '%s';\n", *table.TableType, *table.TableName, strings.ReplaceAll(comment, "'", "''"))
+	}
+	table.DDL = ddl
 	return nil
 }
@@ -0,0 +1,323 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// sqliteColumnType INTEGER | VARCHAR(255) | DECIMAL(10,2) | UNSIGNED BIG INT
+	sqliteColumnType = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_ ]*?)\s*(\(\s*(\d+)\s*(,\s*(\d+)\s*)?\))?$`)
+)
+
+type HelperSqlite struct {
+	app    *App
+	tables []*SchemaTable
+}
+
+func NewSqlite(app *App) Helper {
+	return &HelperSqlite{app: app}
+}
+
+func (s *HelperSqlite) QueryAllTable(ctx context.Context) (err error) {
+	schema := s.app.cfg.TableSchemaName
+	prepare := fmt.Sprintf("SELECT ? AS table_schema, name AS table_name, CASE type WHEN 'view' THEN 'VIEW' ELSE 'BASE TABLE' END AS table_type, '' AS table_comment FROM %s.sqlite_master WHERE ( type IN ( 'table', 'view' ) AND name NOT LIKE 'sqlite_%%' ) ORDER BY name ASC", schema)
+	if err = s.app.takeAll(ctx, &s.tables, prepare, schema); err != nil {
+		return
+	}
+	tasks := make([]func(ctx context.Context) error, 0, len(s.tables))
+	for _, table := range s.tables {
+		table.app = s.app
+		if table.TableComment == nil {
+			table.TableComment = new(string)
+		}
+		table := table
+		tasks = append(tasks, func(ctx context.Context) error {
+			columns, err := s.queryColumns(ctx, schema, table)
+			if err != nil {
+				return err
+			}
+			table.Column = columns
+			if table.isView() {
+				return nil
+			}
+			return s.queryColumnKey(ctx, schema, table)
+		})
+	}
+	return s.app.parallel(ctx, tasks...)
+}
+
+func (s *HelperSqlite) queryColumns(ctx context.Context, schema string, table *SchemaTable) (list []*SchemaColumn, err error) {
+	if schema == "" || table == nil || table.TableName == nil || *table.TableName == "" {
+		return
+	}
+	// the generated columns are only listed by pragma_table_xinfo
+	prepare := "SELECT cid, name, type, \"notnull\", dflt_value, pk, hidden FROM pragma_table_xinfo(?, ?) ORDER BY cid ASC"
+	err = s.app.query(ctx, func(rows *sql.Rows) (err error) {
+		primaryKey := make(map[int]string)
+		for rows.Next() {
+			cid, notNull, pk, hidden := 0, 0, 0, 0
+			name, types := "", ""
+			tmp := &SchemaColumn{}
+			if err = rows.Scan(&cid, &name, &types, &notNull, &tmp.ColumnDefault, &pk, &hidden); err != nil {
+				return
+			}
+			extra := ""
+			switch hidden {
+			case 1:
+				continue // hidden column of virtual table
+			case 2:
+				extra = "VIRTUAL GENERATED"
+			case 3:
+				extra = "STORED GENERATED"
+			}
+			tmp.Extra = &extra
+			position := cid + 1
+			isNullable := "YES"
+			if notNull == 1 || pk > 0 {
+				isNullable = "NO"
+			}
+			columnKey := ""
+			if pk > 0 {
+				columnKey = "PRI"
+				primaryKey[pk] = name
+			}
+			tmp.TableSchema = table.TableSchema
+			tmp.TableName = table.TableName
+			tmp.ColumnName = &name
+			tmp.OrdinalPosition = &position
+			tmp.IsNullable = &isNullable
+			tmp.ColumnKey = &columnKey
+			tmp.ColumnComment = new(string)
+			sqliteParseColumnType(tmp, types)
+			list = append(list, tmp)
+		}
+		// pk is the index of the column in the primary key
+		for i := 1; i <= len(primaryKey); i++ {
+			table.TablePrimaryKey = append(table.TablePrimaryKey, primaryKey[i])
+		}
+		return
+	}, prepare, *table.TableName, schema)
+	if err != nil {
+		return
+	}
+	for _, v := range list {
+		v.table = table
+	}
+	return
+}
+
+// sqliteParseColumnType Split the declared column type into data type, length, precision and scale.
+func sqliteParseColumnType(column *SchemaColumn, types string) {
+	types = strings.TrimSpace(types)
+	columnType := strings.ToLower(types)
+	column.ColumnType = &columnType
+	dataType := columnType
+	result := sqliteColumnType.FindStringSubmatch(columnType)
+	if len(result) == 6 {
+		dataType = strings.TrimSpace(result[1])
+		if result[3] != "" {
+			first, _ := strconv.Atoi(result[3])
+			if result[5] != "" {
+				scale, _ := strconv.Atoi(result[5])
+				column.NumericPrecision = &first
+				column.NumericScale = &scale
+			} else if strings.Contains(dataType, "char") || strings.Contains(dataType, "text") || strings.Contains(dataType, "clob") {
+				column.CharacterMaximumLength = &first
+				column.CharacterOctetLength = &first
+			} else {
+				column.NumericPrecision = &first
+			}
+		}
+	}
+	column.DataType = &dataType
+}
+
+// queryColumnKey Mark the unique, indexed and foreign key columns.
+func (s *HelperSqlite) queryColumnKey(ctx context.Context, schema string, table *SchemaTable) (err error) {
+	if schema == "" || table == nil || table.TableName == nil || *table.TableName == "" {
+		return
+	}
+	type indexColumn struct {
+		index   string
+		unique  int
+		origin  string
+		partial int
+		seqno   int
+		column  string
+	}
+	indexes := make([]*indexColumn, 0)
+	prepare := "SELECT il.name, il.\"unique\", il.origin, il.partial, ii.seqno, COALESCE(ii.name, '') FROM pragma_index_list(?, ?) AS il, pragma_index_info(il.name, ?) AS ii ORDER BY il.seq ASC, ii.seqno ASC"
+	err = s.app.query(ctx, func(rows *sql.Rows) (err error) {
+		for rows.Next() {
+			tmp := &indexColumn{}
+			if err = rows.Scan(&tmp.index, &tmp.unique, &tmp.origin, &tmp.partial, &tmp.seqno, &tmp.column); err != nil {
+				return
+			}
+			indexes = append(indexes, tmp)
+		}
+		return
+	}, prepare, *table.TableName, schema, schema)
+	if err != nil {
+		return
+	}
+	// same as mysql: a single column unique index is 'UNI', the first column of other indexes is 'MUL'
+	indexColumnCount := make(map[string]int)
+	for _, v := range indexes {
+		indexColumnCount[v.index]++
+	}
+	columnKey := make(map[string]string)
+	for _, v := range indexes {
+		if v.origin == "pk" || v.seqno != 0 || v.column == "" || columnKey[v.column] == "UNI" {
+			continue
+		}
+		if v.unique == 1 && indexColumnCount[v.index] == 1 {
+			columnKey[v.column] = "UNI"
+		} else {
+			columnKey[v.column] = "MUL"
+		}
+	}
+	// all indexes, the INTEGER PRIMARY KEY is the rowid which has no index
+	primary := false
+	for i, v := range indexes {
+		if v.seqno != 0 {
+			continue
+		}
+		index := &SchemaIndex{IndexName: v.index, Unique: v.unique == 1, Primary: v.origin == "pk"}
+		for _, w := range indexes[i:] {
+			if w.index != v.index {
+				break
+			}
+			if w.column != "" {
+				index.Columns = append(index.Columns, w.column)
+			}
+		}
+		primary = primary || index.Primary
+		table.TableIndex = append(table.TableIndex, index)
+	}
+	if !primary && len(table.TablePrimaryKey) > 0 {
+		table.TableIndex = append(table.TableIndex, &SchemaIndex{IndexName: "rowid", Unique: true, Primary: true, Columns: table.TablePrimaryKey})
+	}
+	// unique indexes, the expression indexes and the partial indexes are ignored
+	for i, v := range indexes {
+		if v.unique != 1 || v.origin == "pk" || v.partial != 0 || v.seqno != 0 {
+			continue
+		}
+		index := &SchemaIndex{IndexName: v.index, Unique: true}
+		for _, w := range indexes[i:] {
+			if w.index != v.index {
+				break
+			}
+			if w.column == "" {
+				index = nil
+				break
+			}
+			index.Columns = append(index.Columns, w.column)
+		}
+		if index != nil {
+			table.TableUniqueIndex = append(table.TableUniqueIndex, index)
+		}
+	}
+	// the referenced column is null if it refers to the primary key of the parent table
+	prepare = "SELECT id, \"table\", \"from\", COALESCE(\"to\", '') FROM pragma_foreign_key_list(?, ?) ORDER BY id ASC, seq ASC"
+	err = s.app.query(ctx, func(rows *sql.Rows) (err error) {
+		foreignKeys := make(map[int]*SchemaForeignKey)
+		for rows.Next() {
+			id, referenced, from, to := 0, "", "", ""
+			if err = rows.Scan(&id, &referenced, &from, &to); err != nil {
+				return
+			}
+			if _, ok := columnKey[from]; !ok {
+				columnKey[from] = "MUL"
+			}
+			foreignKey, ok := foreignKeys[id]
+			if !ok {
+				foreignKey = &SchemaForeignKey{
+					ReferencedSchema: schema,
+					ReferencedTable:  referenced,
+				}
+				foreignKeys[id] = foreignKey
+				table.TableForeignKey = append(table.TableForeignKey, foreignKey)
+			}
+			foreignKey.Columns = append(foreignKey.Columns, from)
+			foreignKey.ReferencedColumns = append(foreignKey.ReferencedColumns, to)
+		}
+		for _, v := range table.TableForeignKey {
+			v.ConstraintName = fmt.Sprintf("%s_%s_fkey", *table.TableName, strings.Join(v.Columns, "_"))
+		}
+		return
+	}, prepare, *table.TableName, schema)
+	if err != nil {
+		return
+	}
+	for _, c := range table.Column {
+		if c.ColumnKey != nil && *c.ColumnKey == "PRI" {
+			continue
+		}
+		if key, ok := columnKey[*c.ColumnName]; ok {
+			c.ColumnKey = &key
+		}
+	}
+	return
+}
+
+func (s *HelperSqlite) GetAllTable() []*SchemaTable {
+	return s.tables
+}
+
+func (s *HelperSqlite) QueryTableDefineSql(ctx context.Context, table *SchemaTable) error {
+	if table.isView() {
+		return s.queryViewDefineSql(ctx, table)
+	}
+	// INTEGER PRIMARY KEY is an alias for the rowid, which is the auto increment column of sqlite
+	if len(table.TablePrimaryKey) == 1 {
+		for _, c := range table.Column {
+			if *c.ColumnName == table.TablePrimaryKey[0] && c.ColumnType != nil && *c.ColumnType == "integer" {
+				table.TableFieldSerial = *c.ColumnName
+			}
+		}
+	}
+	prepare := fmt.Sprintf("SELECT sql FROM %s.sqlite_master WHERE ( tbl_name = ? AND sql IS NOT NULL ) ORDER BY CASE type WHEN 'table' THEN 0 ELSE 1 END ASC, name ASC", *table.TableSchema)
+	result := make([]string, 0, 4)
+	err := s.app.query(ctx, func(rows *sql.Rows) error {
+		for rows.Next() {
+			tmp := ""
+			if err := rows.Scan(&tmp); err != nil {
+				return err
+			}
+			result = append(result, tmp)
+		}
+		return nil
+	}, prepare, *table.TableName)
+	if err != nil {
+		return err
+	}
+	ddl := strings.Join(result, ";\n")
+	ddl = strings.Replace(ddl, "CREATE TABLE", "CREATE TABLE IF NOT EXISTS", 1)
+	ddl = strings.ReplaceAll(ddl, "CREATE INDEX", "CREATE INDEX IF NOT EXISTS")
+	ddl = strings.ReplaceAll(ddl, "CREATE UNIQUE INDEX", "CREATE UNIQUE INDEX IF NOT EXISTS")
+	table.DDL = ddl
+	return nil
+}
+
+func (s *HelperSqlite) queryViewDefineSql(ctx context.Context, table *SchemaTable) error {
+	prepare := fmt.Sprintf("SELECT sql FROM %s.sqlite_master WHERE ( type = 'view' AND name = ? )", *table.TableSchema)
+	result := ""
+	err := s.app.query(ctx, func(rows *sql.Rows) error {
+		for rows.Next() {
+			if err := rows.Scan(&result); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, prepare, *table.TableName)
+	if err != nil {
+		return err
+	}
+	table.DDL = createViewRegexpReplace.ReplaceAllString(result, "CREATE VIEW IF NOT EXISTS ")
+	return nil
+}
@@ -14,9 +14,30 @@ var (
 	//go:embed tmpl/model_schema_content_primary_key.tmpl
 	tmplModelSchemaContentPrimaryKey []byte
 
-	//go:embed tmpl/pgsql/func_create.sql
-	pgsqlFuncCreate string
+	//go:embed tmpl/model_schema_content_primary_key_composite.tmpl
+	tmplModelSchemaContentPrimaryKeyComposite []byte
 
-	//go:embed tmpl/pgsql/func_drop.sql
-	pgsqlFuncDrop string
+	//go:embed tmpl/model_schema_content_unique_index.tmpl
+	tmplModelSchemaContentUniqueIndex []byte
+
+	//go:embed tmpl/model_schema_content_relation.tmpl
+	tmplModelSchemaContentRelation []byte
+
+	//go:embed tmpl/model_enum.tmpl
+	tmplModelEnum []byte
+
+	//go:embed tmpl/model_decimal.tmpl
+	tmplModelDecimal []byte
+
+	//go:embed tmpl/model_bit.tmpl
+	tmplModelBit []byte
+
+	//go:embed tmpl/model_network.tmpl
+	tmplModelNetwork []byte
+
+	//go:embed tmpl/model_interval.tmpl
+	tmplModelInterval []byte
+
+	//go:embed tmpl/model_json.tmpl
+	tmplModelJson []byte
 )
@@ -1,2 +0,0 @@
-DROP FUNCTION IF EXISTS show_create_table_schema;
-DROP FUNCTION IF EXISTS show_create_table;
\ No newline at end of file
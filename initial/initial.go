@@ -2,11 +2,14 @@ package initial
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"github.com/cd365/hey-template/app"
 	"github.com/cd365/hey-template/values"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 func Start() {
@@ -73,13 +76,21 @@ func Start() {
 		}
 	}
 
-	sss, err := inject(context.Background(), cfg)
+	// stop querying the database on SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	sss, err := inject(ctx, cfg)
 	if err != nil {
 		fmt.Println("initial failed.", err.Error())
 		return
 	}
 
 	if err = sss.BuildAll(); err != nil {
+		if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+			fmt.Println("interrupted")
+			return
+		}
 		fmt.Println(err.Error())
 	}
 